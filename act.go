@@ -12,6 +12,8 @@ import (
 	"os"
 	"reflect"
 	"strconv"
+	"strings"
+	"text/template"
 	"time"
 
 	"github.com/iancoleman/strcase"
@@ -21,6 +23,17 @@ import (
 var (
 	ErrInvalidConfigType = errors.New("invalid config type")
 	ErrUnsupportedType   = errors.New("type not supported")
+	ErrRequired          = errors.New("required value not set")
+	ErrConfigFormat      = errors.New("unsupported config file format")
+	ErrDotEnvSyntax      = errors.New("invalid dotenv syntax")
+	ErrInvalidMapItem    = errors.New("invalid map item, expected key=value")
+	ErrDuplicateField    = errors.New("duplicate field")
+	ErrInvalidArgument   = errors.New("invalid positional argument")
+	ErrInvalidShort      = errors.New("invalid short flag")
+	ErrUnknownKey        = errors.New("unknown config key")
+	ErrValidation        = errors.New("validation failed")
+	ErrNoCommand         = errors.New("command not specified")
+	ErrUnknownCommand    = errors.New("unknown command")
 )
 
 // Act is an abstraction of a CLI command.
@@ -28,8 +41,30 @@ type Act struct {
 	flagSet       *flag.FlagSet
 	output        io.Writer
 	lookupEnvFunc func(string) (string, bool)
+	environFunc   func() []string
+	sources       []Source
+	fields        []*fieldMeta
+	args          []*argMeta
+	shorts        map[string]string
+	validators    []*validatorMeta
+	errs          ParseErrors
+	finalizers    []func()
+	parent        *Act
+	commands      []*Act
+	config        interface{}
+	handler       Handler
 	name          string
+	description   string
+	configPath    string
+	dotEnvPaths   []string
+	examples      []string
+	helpTemplate  *template.Template
+	opts          []Option
+	watchInterval time.Duration
 	errorHandling flag.ErrorHandling
+	configFlag    bool
+	posix         bool
+	groupedHelp   bool
 	help          bool
 }
 
@@ -39,10 +74,15 @@ func New(name string, opts ...Option) *Act {
 		flagSet:       flag.NewFlagSet(name, flag.ContinueOnError),
 		output:        os.Stderr,
 		lookupEnvFunc: os.LookupEnv,
+		environFunc:   os.Environ,
 		name:          name,
+		opts:          opts,
+		watchInterval: time.Second,
 		errorHandling: flag.ExitOnError,
 	}
 
+	a.flagSet.Usage = a.printUsage
+
 	for _, opt := range opts {
 		opt(a)
 	}
@@ -54,17 +94,80 @@ func New(name string, opts ...Option) *Act {
 
 // Parse parses command line flags, environment variables and default values.
 // It populates supplied pointer to configuration struct with values according to the order of precedence.
+// Errors of all the fields which failed to parse are returned together as ParseErrors.
 func (a *Act) Parse(config interface{}, flags []string) error {
-	if err := a.parse(config, flags, ""); err != nil {
+	if err := a.parseFlags(config, flags); err != nil {
 		return a.exit(err)
 	}
 
-	return a.exit(a.flagSet.Parse(flags))
+	if errs := a.bindArgs(); len(errs) > 0 {
+		return a.exit(errs)
+	}
+
+	return a.exit(a.finish())
 }
 
-func (a *Act) parse(config interface{}, flags []string, prefix string) error { //nolint:cyclop
+// parseFlags populates the config from sources, registers the flags and parses them.
+func (a *Act) parseFlags(config interface{}, flags []string) error {
 	a.parseHelp(flags)
 
+	if err := a.initSources(flags); err != nil {
+		return err
+	}
+
+	a.own(config)
+
+	if err := a.parse(config, flags, ""); err != nil {
+		return err
+	}
+
+	a.checkKeys()
+
+	// Invalid default values are reported instead of the help.
+	if a.help && len(a.errs) > 0 {
+		return a.errs
+	}
+
+	a.inheritFlags()
+	a.inheritShorts()
+	a.wrapFlags()
+
+	if err := a.flagSet.Parse(a.translateFlags(flags)); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if len(a.errs) > 0 {
+		return a.errs
+	}
+
+	return nil
+}
+
+// finish completes parsing once all the flags are parsed, including flags of the subcommands.
+func (a *Act) finish() error {
+	a.visitFlags()
+
+	for _, finalize := range a.finalizers {
+		finalize()
+	}
+
+	if a.help {
+		return nil
+	}
+
+	if errs := append(a.checkRequired(), a.validate()...); len(errs) > 0 {
+		return errs
+	}
+
+	// Validator is called only if all the fields are valid.
+	if errs := a.callValidators(); len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (a *Act) parse(config interface{}, flags []string, prefix string) error { //nolint:cyclop
 	v := reflect.ValueOf(config)
 	t := reflect.TypeOf(config)
 
@@ -77,45 +180,478 @@ func (a *Act) parse(config interface{}, flags []string, prefix string) error { /
 	for i := 0; i < v.NumField(); i++ {
 		field := t.Elem().Field(i)
 
+		if ignored(field) {
+			continue
+		}
+
+		if _, ok := field.Tag.Lookup("arg"); ok {
+			a.parseArg(field, v.Field(i), prefix)
+
+			continue
+		}
+
 		flagName := a.flagName(field, prefix)
 
 		envVarName := a.envVarName(field, prefix)
 
-		usage := a.usage(field, envVarName, prefix)
+		required := field.Tag.Get("req") == "true"
+
+		secret := a.secret(field)
+
+		short := a.shortName(field, prefix)
 
-		p := v.FieldByName(field.Name).Addr().Interface()
+		usage := a.usage(field, envVarName, prefix, short, required)
 
-		// Recurse if got struct which is not of URL type
-		_, oku := p.(*URL)
-		_, okt := p.(*Time)
+		p := v.Field(i).Addr().Interface()
 
-		if field.Type.Kind() == reflect.Struct && !oku && !okt {
-			if err := a.parse(p, flags, a.newPrefix(field, prefix)); err != nil {
+		// Embedded structs are flattened into the parent namespace.
+		nested := a.newPrefix(field, prefix)
+		if field.Anonymous {
+			nested = prefix
+		}
+
+		// Recurse if got struct which doesn't implement flag.Value or encoding.TextUnmarshaler, like URL type
+		if field.Type.Kind() == reflect.Struct && !isValueType(field.Type) {
+			if err := a.parse(p, flags, nested); err != nil {
 				return err
 			}
 
+			// Validate method of the embedded struct is promoted to the parent, or overridden by the parent's own, so
+			// it is left to the parent's validator registered last.
+			_, embedded := p.(Validator)
+			if _, ok := config.(Validator); ok && embedded && field.Anonymous {
+				a.validators = a.validators[:len(a.validators)-1]
+			}
+
 			continue
 		}
 
-		envVarValue, ok := a.lookupEnvFunc(envVarName)
-		if ok && !a.help {
-			if err := a.parseValue(field.Type.Kind(), p, flagName, envVarValue, usage); err != nil {
-				return fmt.Errorf("%s env: %w", field.Name, err)
+		if field.Type.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct &&
+			!isValueType(field.Type.Elem()) {
+			if err := a.parseOptional(v.Field(i), flags, nested); err != nil {
+				return err
 			}
 
 			continue
 		}
 
-		if err := a.parseValue(field.Type.Kind(), p, flagName, field.Tag.Get("def"), usage); err != nil {
-			return fmt.Errorf("%s def: %w", field.Name, err)
+		if isStructSliceType(field.Type) {
+			if err := a.parseStructSlice(v.Field(i), flags, a.newPrefix(field, prefix)); err != nil {
+				return err
+			}
+
+			continue
 		}
+
+		f := &fieldMeta{
+			Field: Field{
+				Path: a.path(field, prefix),
+				Flag: flagName,
+				Env:  envVarName,
+				Tag:  field.Tag,
+				Type: field.Type,
+			},
+			source:   "",
+			location: "",
+			value:    "",
+			arg:      "",
+			short:    "",
+			help:     a.helpText(field, prefix),
+			rv:       v.Field(i),
+			required: required,
+			secret:   secret,
+			absent:   false,
+			set:      false,
+		}
+
+		if a.duplicate(f.Field) {
+			a.errs = append(a.errs, f.error("", "", "", fmt.Errorf("%w (flag -%s)", ErrDuplicateField, flagName)))
+
+			continue
+		}
+
+		a.fields = append(a.fields, f)
+
+		value, source, ok, err := a.lookup(f.Field)
+		if err != nil {
+			a.errs = append(a.errs, a.fieldError(f, source, value, err))
+
+			continue
+		}
+
+		f.set = ok
+
+		if ok {
+			f.origin(source, value)
+		}
+
+		switch {
+		case isSliceType(field.Type):
+			err = a.parseSlice(v.Field(i), flagName, value, usage, separator(field.Tag))
+		case isMapType(field.Type):
+			err = a.parseMap(v.Field(i), flagName, value, usage, separator(field.Tag))
+		default:
+			err = a.parseValue(p, flagName, value, usage)
+		}
+
+		if err != nil {
+			a.errs = append(a.errs, a.fieldError(f, source, value, err))
+
+			continue
+		}
+
+		if fl := a.flagSet.Lookup(flagName); fl != nil && secret && value != "" {
+			fl.DefValue = mask
+		}
+
+		if short == "" {
+			continue
+		}
+
+		if err := a.addShort(f, short); err != nil {
+			a.errs = append(a.errs, f.error("", "", "", err))
+		}
+	}
+
+	// Nested structs are validated before their parents.
+	if vr, ok := config.(Validator); ok {
+		a.validators = append(a.validators, &validatorMeta{
+			path:      strings.ReplaceAll(prefix, "-", "."),
+			validator: vr,
+			absent:    false,
+		})
 	}
 
 	return nil
 }
 
+// duplicate reports whether the field has the same path or flag name as any of the fields parsed before, i.e. if
+// embedded struct contains the field of the same name. Positional arguments have no flag name.
+func (a *Act) duplicate(field Field) bool {
+	if field.Flag != "" && a.flagSet.Lookup(field.Flag) != nil {
+		return true
+	}
+
+	for _, f := range a.fields {
+		if f.Path == field.Path || (field.Flag != "" && f.Flag == field.Flag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fieldError creates an error of the field which value from the source failed to parse.
+func (*Act) fieldError(field *fieldMeta, source Source, value string, err error) *FieldError {
+	if source == nil {
+		return field.error("def", "", value, err)
+	}
+
+	location := ""
+	if l, ok := source.(locator); ok {
+		location = l.location(field.Field)
+	}
+
+	return field.error(source.Name(), location, value, err)
+}
+
+// parseOptional parses pointer to struct field, which stays nil unless any of its fields is set by some source.
+func (a *Act) parseOptional(v reflect.Value, flags []string, prefix string) error {
+	if !v.IsNil() {
+		return a.parse(v.Interface(), flags, prefix)
+	}
+
+	p := reflect.New(v.Type().Elem())
+	start, vstart := len(a.fields), len(a.validators)
+
+	if err := a.parse(p.Interface(), flags, prefix); err != nil {
+		return err
+	}
+
+	fields, validators := a.fields[start:], a.validators[vstart:]
+
+	a.finalizers = append(a.finalizers, func() {
+		for _, f := range fields {
+			if f.set {
+				v.Set(p)
+
+				return
+			}
+		}
+
+		// Fields of the absent optional struct are neither required nor validated.
+		for _, f := range fields {
+			f.required = false
+			f.absent = true
+		}
+
+		for _, v := range validators {
+			v.absent = true
+		}
+	})
+
+	return nil
+}
+
+// parseStructSlice parses slice of structs, which elements are configured by indexed flags and environment
+// variables, i.e. -backends-0-host and APP_BACKENDS_0_HOST. The slice ends before the first index without any value
+// set by flags or sources, except default values. In help mode at least one element is shown.
+func (a *Act) parseStructSlice(v reflect.Value, flags []string, prefix string) error {
+	args := argFlags(flags)
+
+	n := 0
+	for a.elementSet(v.Type().Elem(), fmt.Sprintf("%s-%d", prefix, n), args) {
+		n++
+	}
+
+	if n == 0 && !a.help {
+		return nil
+	}
+
+	s := reflect.MakeSlice(v.Type(), n, n)
+	if n == 0 {
+		s = reflect.MakeSlice(v.Type(), 1, 1)
+	}
+
+	for i := 0; i < s.Len(); i++ {
+		if err := a.parse(s.Index(i).Addr().Interface(), flags, fmt.Sprintf("%s-%d", prefix, i)); err != nil {
+			return err
+		}
+	}
+
+	if n > 0 {
+		v.Set(s)
+	}
+
+	return nil
+}
+
+// elementSet reports whether any field of the slice element is set by flags or sources, except default values.
+func (a *Act) elementSet(t reflect.Type, prefix string, args map[string]bool) bool {
+	for _, f := range a.structFields(t, prefix) {
+		if args[f.Flag] {
+			return true
+		}
+
+		if a.help {
+			continue
+		}
+
+		for _, s := range a.sources {
+			if _, ok := s.(defSource); ok {
+				continue
+			}
+
+			if _, ok, err := s.Lookup(f); ok || err != nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// structFields returns fields of the struct type, recursing into nested structs the same way as parse does.
+func (a *Act) structFields(t reflect.Type, prefix string) []Field {
+	var fields []Field
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		if _, ok := sf.Tag.Lookup("arg"); ok || ignored(sf) {
+			continue
+		}
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr && ft.Elem().Kind() == reflect.Struct && !isValueType(ft.Elem()) {
+			ft = ft.Elem()
+		}
+
+		if ft.Kind() == reflect.Struct && !isValueType(ft) {
+			nested := a.newPrefix(sf, prefix)
+			if sf.Anonymous {
+				nested = prefix
+			}
+
+			fields = append(fields, a.structFields(ft, nested)...)
+
+			continue
+		}
+
+		fields = append(fields, Field{
+			Path: a.path(sf, prefix),
+			Flag: a.flagName(sf, prefix),
+			Env:  a.envVarName(sf, prefix),
+			Tag:  sf.Tag,
+			Type: sf.Type,
+		})
+	}
+
+	return fields
+}
+
+// initSources sets up the default chain of sources unless set by WithSources option and loads them.
+func (a *Act) initSources(flags []string) error {
+	if a.configFlag {
+		a.parseConfigFlag(flags)
+	}
+
+	if a.sources == nil {
+		a.sources = []Source{&envSource{lookupEnvFunc: a.lookupEnvFunc, environFunc: a.environFunc, owned: nil}}
+
+		if len(a.dotEnvPaths) > 0 {
+			a.sources[0] = &dotEnvSource{
+				lookupEnvFunc: a.lookupEnvFunc,
+				environFunc:   a.environFunc,
+				paths:         a.dotEnvPaths,
+				values:        nil,
+				owned:         nil,
+			}
+		}
+
+		if a.configPath != "" {
+			a.sources = append(a.sources, FileSource(a.configPath))
+		}
+
+		a.sources = append(a.sources, DefSource())
+	}
+
+	if a.help {
+		return nil
+	}
+
+	for _, s := range a.sources {
+		if l, ok := s.(loader); ok {
+			if err := l.load(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseConfigFlag adds config flag and sets the config file path by the flag or environment variable.
+func (a *Act) parseConfigFlag(flags []string) {
+	envVarName := strcase.ToScreamingSnake(fmt.Sprintf("%s_config", a.envPrefix()))
+
+	if path, ok := a.lookupEnvFunc(envVarName); ok {
+		a.configPath = path
+	}
+
+	a.flagSet.StringVar(&a.configPath, "config", a.configPath, fmt.Sprintf("config file (env %s)", envVarName))
+
+	for i, f := range flags {
+		if f == "--" {
+			break
+		}
+
+		switch {
+		case (f == "-config" || f == "--config") && i+1 < len(flags):
+			a.configPath = flags[i+1]
+		case strings.HasPrefix(f, "-config=") || strings.HasPrefix(f, "--config="):
+			a.configPath = f[strings.Index(f, "=")+1:]
+		}
+	}
+}
+
+// own tells the sources collecting families of environment variables about all the fields of the config, so the
+// variables of other fields are not collected by map fields.
+func (a *Act) own(config interface{}) {
+	t := reflect.TypeOf(config)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return
+	}
+
+	fields := a.structFields(t.Elem(), "")
+
+	for _, s := range a.sources {
+		if o, ok := s.(owner); ok {
+			o.own(fields)
+		}
+	}
+}
+
+// lookup returns the value from the first source in chain which contains the field together with the source.
+// In help mode only default values are used.
+func (a *Act) lookup(field Field) (string, Source, bool, error) {
+	for _, s := range a.sources {
+		if _, ok := s.(defSource); a.help && !ok {
+			continue
+		}
+
+		v, ok, err := s.Lookup(field)
+		if err != nil {
+			return "", s, false, err //nolint:wrapcheck
+		}
+
+		if ok {
+			return v, s, true, nil
+		}
+	}
+
+	return "", nil, false, nil
+}
+
+// checkKeys records errors of keys not mapped to any field found in sources.
+func (a *Act) checkKeys() {
+	if a.help {
+		return
+	}
+
+	fields := make([]Field, 0, len(a.fields))
+	for _, f := range a.fields {
+		if f.arg == "" {
+			fields = append(fields, f.Field)
+		}
+	}
+
+	for _, s := range a.sources {
+		if kc, ok := s.(keyChecker); ok {
+			a.errs = append(a.errs, kc.checkKeys(fields)...)
+		}
+	}
+}
+
+// visitFlags marks fields set by command line flags, including the fields of parent commands.
+func (a *Act) visitFlags() {
+	a.flagSet.Visit(func(f *flag.Flag) {
+		for c := a; c != nil; c = c.parent {
+			for _, field := range c.fields {
+				if field.Flag == f.Name {
+					field.set = true
+					field.source = "flag"
+					field.location = ""
+					field.value = f.Value.String()
+				}
+			}
+		}
+	})
+}
+
+// checkRequired returns errors of all required fields not set by any source.
+func (a *Act) checkRequired() ParseErrors {
+	var errs ParseErrors
+
+	for _, field := range a.fields {
+		if field.required && !field.set && field.arg != "" {
+			errs = append(errs, field.error("", "", "", fmt.Errorf("%w (argument %s)", ErrRequired, field.arg)))
+
+			continue
+		}
+
+		if field.required && !field.set {
+			errs = append(errs, field.error("", "", "", fmt.Errorf("%w (flag -%s, env %s)", ErrRequired,
+				field.Flag, field.Env)))
+		}
+	}
+
+	return errs
+}
+
 func (*Act) flagName(sf reflect.StructField, prefix string) string {
-	if f := sf.Tag.Get("flag"); f != "" {
+	// Tags are ignored within the elements of slices, so names stay unique.
+	if f := sf.Tag.Get("flag"); f != "" && !indexed(prefix) {
 		return f
 	}
 
@@ -129,21 +665,103 @@ func (*Act) flagName(sf reflect.StructField, prefix string) string {
 }
 
 func (a *Act) envVarName(sf reflect.StructField, prefix string) string {
-	if e := sf.Tag.Get("env"); e != "" {
+	if e := sf.Tag.Get("env"); e != "" && !indexed(prefix) {
 		return e
 	}
 
-	n := fmt.Sprintf("%s_%s", a.name, sf.Name)
+	n := fmt.Sprintf("%s_%s", a.envPrefix(), sf.Name)
 	if prefix != "" {
-		n = fmt.Sprintf("%s_%s_%s", a.name, prefix, sf.Name)
+		n = fmt.Sprintf("%s_%s_%s", a.envPrefix(), prefix, sf.Name)
 	}
 
 	return strcase.ToScreamingSnake(n)
 }
 
-func (*Act) usage(sf reflect.StructField, env string, prefix string) string {
+// ignored reports whether the field is unexported, including embedded structs of unexported types, or tagged by
+// `act:"-"`, i.e. logger or client kept in the config struct.
+func ignored(sf reflect.StructField) bool {
+	return !sf.IsExported() || sf.Tag.Get("act") == "-"
+}
+
+// indexed reports whether the prefix belongs to the element of a slice, i.e. "Backends-0".
+func indexed(prefix string) bool {
+	for _, p := range strings.Split(prefix, "-") {
+		if _, err := strconv.Atoi(p); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// argFlags returns names of the flags found in the arguments before the terminator.
+func argFlags(args []string) map[string]bool {
+	names := map[string]bool{}
+
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		if i := strings.IndexByte(name, '='); i != -1 {
+			name = name[:i]
+		}
+
+		names[name] = true
+	}
+
+	return names
+}
+
+// envPrefix returns command name prefixed by the names of parent commands, i.e. "app_db_migrate".
+func (a *Act) envPrefix() string {
+	if a.parent != nil {
+		return fmt.Sprintf("%s_%s", a.parent.envPrefix(), a.name)
+	}
+
+	return a.name
+}
+
+// secret reports whether the field holds a secret, either by "secret" tag or by the last word of its name,
+// i.e. DBPassword, but not TokenExpiration.
+func (*Act) secret(sf reflect.StructField) bool {
+	if s, ok := sf.Tag.Lookup("secret"); ok {
+		return s == "true"
+	}
+
+	words := strings.Split(strcase.ToSnake(sf.Name), "_")
+
+	switch words[len(words)-1] {
+	case "password", "passwd", "secret", "token":
+		return true
+	}
+
+	return false
+}
+
+func (a *Act) usage(sf reflect.StructField, env, prefix, short string, required bool) string {
+	u := a.helpText(sf, prefix)
+
+	if short != "" {
+		u = fmt.Sprintf("%s (short -%s)", u, short)
+	}
+
+	if required {
+		return fmt.Sprintf("%s (env %s) (required)", u, env)
+	}
+
+	return fmt.Sprintf("%s (env %s)", u, env)
+}
+
+// helpText returns the help tag or the human friendly name of the field, i.e. "db postgres host".
+func (*Act) helpText(sf reflect.StructField, prefix string) string {
 	if u := sf.Tag.Get("help"); u != "" {
-		return fmt.Sprintf("%s (env %s)", u, env)
+		return u
 	}
 
 	n := sf.Name
@@ -151,7 +769,15 @@ func (*Act) usage(sf reflect.StructField, env string, prefix string) string {
 		n = fmt.Sprintf("%s %s", prefix, sf.Name)
 	}
 
-	return fmt.Sprintf("%s (env %s)", strcase.ToDelimited(n, ' '), env)
+	return strcase.ToDelimited(n, ' ')
+}
+
+func (*Act) path(sf reflect.StructField, prefix string) string {
+	if prefix != "" {
+		return fmt.Sprintf("%s.%s", strings.ReplaceAll(prefix, "-", "."), sf.Name)
+	}
+
+	return sf.Name
 }
 
 func (a *Act) parseHelp(flags []string) {
@@ -160,6 +786,11 @@ func (a *Act) parseHelp(flags []string) {
 	}
 
 	for _, f := range flags {
+		// The rest of the flags belong to the subcommand.
+		if f == "--" || a.command(f) != nil {
+			return
+		}
+
 		if f == "--help" || f == "-help" || f == "--h" || f == "-h" {
 			a.help = true
 
@@ -176,46 +807,41 @@ func (*Act) newPrefix(sf reflect.StructField, prefix string) string {
 	return sf.Name
 }
 
-func (a *Act) parseValue(kind reflect.Kind, varPointer interface{}, flag, value, usage string) error { //nolint:cyclop
-	switch kind { //nolint:exhaustive
-	case reflect.Bool:
-		return a.parseBool(varPointer.(*bool), flag, value, usage) //nolint:forcetypeassert
-	case reflect.String:
-		a.flagSet.StringVar(varPointer.(*string), flag, value, usage) //nolint:forcetypeassert
+func (a *Act) parseValue(varPointer interface{}, flag, value, usage string) error { //nolint:cyclop
+	switch varPointer := varPointer.(type) {
+	case *bool:
+		return a.parseBool(varPointer, flag, value, usage)
+	case *string:
+		a.flagSet.StringVar(varPointer, flag, value, usage)
 
 		return nil
-	case reflect.Uint:
-		return a.parseUint(varPointer.(*uint), flag, value, usage) //nolint:forcetypeassert
-	case reflect.Uint64:
-		return a.parseUint64(varPointer.(*uint64), flag, value, usage) //nolint:forcetypeassert
-	case reflect.Int:
-		return a.parseInt(varPointer.(*int), flag, value, usage) //nolint:forcetypeassert
-	case reflect.Int64:
-		switch varPointer := varPointer.(type) {
-		case *time.Duration:
-			return a.parseDuration(varPointer, flag, value, usage)
-		case *int64:
-			return a.parseInt64(varPointer, flag, value, usage)
-		}
-	case reflect.Float64:
-		return a.parseFloat64(varPointer.(*float64), flag, value, usage) //nolint:forcetypeassert
-	case reflect.Struct:
-		switch varPointer := varPointer.(type) {
-		case *URL:
-			return a.parseURL(varPointer, flag, value, usage)
-		case *Time:
-			return a.parseTime(varPointer, flag, value, usage)
-		}
-	case reflect.Slice:
-		switch varPointer := varPointer.(type) {
-		case *StringSlice:
-			return a.parseStringSlice(varPointer, flag, value, usage)
-		case *IntSlice:
-			return a.parseIntSlice(varPointer, flag, value, usage)
-		}
-	}
-
-	return fmt.Errorf("parsing value: %w: %v", ErrUnsupportedType, kind)
+	case *uint:
+		return a.parseUint(varPointer, flag, value, usage)
+	case *uint64:
+		return a.parseUint64(varPointer, flag, value, usage)
+	case *int:
+		return a.parseInt(varPointer, flag, value, usage)
+	case *int64:
+		return a.parseInt64(varPointer, flag, value, usage)
+	case *time.Duration:
+		return a.parseDuration(varPointer, flag, value, usage)
+	case *float64:
+		return a.parseFloat64(varPointer, flag, value, usage)
+	case *URL:
+		return a.parseURL(varPointer, flag, value, usage)
+	case *Time:
+		return a.parseTime(varPointer, flag, value, usage)
+	case *StringSlice:
+		return a.parseStringSlice(varPointer, flag, value, usage)
+	case *IntSlice:
+		return a.parseIntSlice(varPointer, flag, value, usage)
+	}
+
+	if v, ok := flagValue(varPointer); ok {
+		return a.parseVar(v, reflect.TypeOf(varPointer).Elem(), flag, value, usage)
+	}
+
+	return a.parseReflect(reflect.ValueOf(varPointer).Elem(), flag, value, usage)
 }
 
 func (a *Act) parseBool(p *bool, flag, value, usage string) error {
@@ -242,9 +868,9 @@ func (a *Act) parseUint(p *uint, flag, value, usage string) error {
 		return nil
 	}
 
-	val, err := strconv.ParseUint(value, 10, 32) //nolint:gomnd
+	val, err := strconv.ParseUint(value, 10, strconv.IntSize)
 	if err != nil {
-		return fmt.Errorf("parsing uint %q: %w", value, err)
+		return rangeError(reflect.TypeOf(*p), value, err, uintRange(strconv.IntSize))
 	}
 
 	a.flagSet.UintVar(p, flag, uint(val), usage)
@@ -261,7 +887,7 @@ func (a *Act) parseUint64(p *uint64, flag, value, usage string) error {
 
 	val, err := strconv.ParseUint(value, 10, 64) //nolint:gomnd
 	if err != nil {
-		return fmt.Errorf("parsing uint64 %q: %w", value, err)
+		return rangeError(reflect.TypeOf(*p), value, err, uintRange(64)) //nolint:gomnd
 	}
 
 	a.flagSet.Uint64Var(p, flag, val, usage)
@@ -278,7 +904,7 @@ func (a *Act) parseInt(p *int, flag, value, usage string) error {
 
 	val, err := strconv.Atoi(value)
 	if err != nil {
-		return fmt.Errorf("parsing int %q: %w", value, err)
+		return rangeError(reflect.TypeOf(*p), value, err, intRange(strconv.IntSize))
 	}
 
 	a.flagSet.IntVar(p, flag, val, usage)
@@ -295,7 +921,7 @@ func (a *Act) parseInt64(p *int64, flag, value, usage string) error {
 
 	val, err := strconv.ParseInt(value, 10, 64) //nolint:gomnd
 	if err != nil {
-		return fmt.Errorf("parsing int64 %q: %w", value, err)
+		return rangeError(reflect.TypeOf(*p), value, err, intRange(64)) //nolint:gomnd
 	}
 
 	a.flagSet.Int64Var(p, flag, val, usage)
@@ -329,7 +955,7 @@ func (a *Act) parseFloat64(p *float64, flag, value, usage string) error {
 
 	val, err := strconv.ParseFloat(value, 64) //nolint:gomnd
 	if err != nil {
-		return fmt.Errorf("parsing float64 %q: %w", value, err)
+		return rangeError(reflect.TypeOf(*p), value, err, floatRange(64)) //nolint:gomnd
 	}
 
 	a.flagSet.Float64Var(p, flag, val, usage)
@@ -415,6 +1041,68 @@ func (a *Act) parseTime(p *Time, flag, value, usage string) error {
 	return nil
 }
 
+func (a *Act) parseVar(p flag.Value, t reflect.Type, flag, value, usage string) error {
+	if value != "" {
+		if err := p.Set(value); err != nil {
+			return fmt.Errorf("parsing %s %q: %w", t, value, err)
+		}
+	}
+
+	a.flagSet.Var(p, flag, usage)
+
+	return nil
+}
+
+// parseReflect handles the rest of supported kinds, including named types like `type Mode string`.
+func (a *Act) parseReflect(v reflect.Value, flag, value, usage string) error {
+	if !isSupported(v.Type()) {
+		return fmt.Errorf("parsing value: %w: %v", ErrUnsupportedType, v.Type())
+	}
+
+	if value != "" {
+		if err := setValue(v, value); err != nil {
+			return err
+		}
+	}
+
+	a.flagSet.Var(&reflectValue{v: v}, flag, usage)
+
+	if v.IsZero() {
+		// Zero value of reflectValue doesn't know its type, so it can't format zero default value itself.
+		a.flagSet.Lookup(flag).DefValue = ""
+	}
+
+	return nil
+}
+
+// parseSlice parses native slice field, which elements are separated by the separator.
+func (a *Act) parseSlice(v reflect.Value, flag, value, usage, sep string) error {
+	items, err := splitValue(v.Type(), value, sep)
+	if err != nil {
+		return err
+	}
+
+	v.Set(items)
+
+	a.flagSet.Var(&sliceValue{v: v, sep: sep, set: false}, flag, usage)
+
+	return nil
+}
+
+// parseMap parses native map field, which key=value items are separated by the separator.
+func (a *Act) parseMap(v reflect.Value, flag, value, usage, sep string) error {
+	items, err := splitMap(v.Type(), value, sep)
+	if err != nil {
+		return err
+	}
+
+	v.Set(items)
+
+	a.flagSet.Var(&mapValue{v: v, sep: sep, set: false}, flag, usage)
+
+	return nil
+}
+
 func (a *Act) exit(err error) error {
 	if err == nil {
 		return nil
@@ -441,6 +1129,83 @@ func (a *Act) exit(err error) error {
 	return nil
 }
 
+// fieldMeta holds the metadata of a single config value collected while parsing.
+type fieldMeta struct {
+	Field
+	source   string
+	location string
+	value    string
+	arg      string
+	short    string
+	help     string
+	rv       reflect.Value
+	required bool
+	secret   bool
+	absent   bool
+	set      bool
+}
+
+// error creates an error of the field, hiding the value of the secret field.
+func (f *fieldMeta) error(source, location, value string, err error) *FieldError {
+	if f.secret && value != "" {
+		err = &secretError{err: err, value: value, items: f.items(value)}
+		value = mask
+	}
+
+	return &FieldError{
+		Path:     f.Path,
+		Flag:     f.Flag,
+		Env:      f.Env,
+		Source:   source,
+		Location: location,
+		Value:    value,
+		Err:      err,
+	}
+}
+
+// items splits the value of the slice or map field into elements and keys and values of map items, so the element
+// which failed to parse may be hidden as well.
+func (f *fieldMeta) items(value string) []string {
+	t := f.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Map {
+		return nil
+	}
+
+	items := []string{value}
+
+	for _, sep := range []string{separator(f.Tag), ",", listSep} {
+		var split []string
+
+		for _, item := range items {
+			split = append(split, strings.Split(item, sep)...)
+		}
+
+		items = split
+	}
+
+	for _, item := range items {
+		if i := strings.IndexByte(item, '='); i != -1 {
+			items = append(items, item[:i], item[i+1:])
+		}
+	}
+
+	return items
+}
+
+// origin records the source which has set the value.
+func (f *fieldMeta) origin(source Source, value string) {
+	f.source = source.Name()
+	f.value = value
+
+	if l, ok := source.(locator); ok {
+		f.location = l.location(f.Field)
+	}
+}
+
 // Option defines optional parameters to the constructor.
 type Option func(a *Act)
 
@@ -465,12 +1230,99 @@ func WithLookupEnvFunc(fn func(string) (string, bool)) Option {
 	}
 }
 
+// WithConfigFile is an option to read values from JSON, YAML or TOML file, see FileSource.
+// Values from the file take precedence over default values, but not over flags and environment variables.
+func WithConfigFile(path string) Option {
+	return func(a *Act) {
+		a.configPath = path
+	}
+}
+
+// WithConfigFlag is an option to add config flag and respective environment variable to set the config file path.
+func WithConfigFlag() Option {
+	return func(a *Act) {
+		a.configFlag = true
+	}
+}
+
+// WithEnvironFunc may be used to override default os.Environ function to discover families of environment variables
+// of map fields, i.e. APP_HEADERS_<KEY>. Values are still read by the lookup function.
+func WithEnvironFunc(fn func() []string) Option {
+	return func(a *Act) {
+		a.environFunc = fn
+	}
+}
+
+// WithDotEnv is an option to read environment variables from dotenv files as well, i.e. ".env". Variables set in
+// the process environment take precedence, followed by the files in the order they are listed. Files which don't
+// exist are skipped.
+func WithDotEnv(paths ...string) Option {
+	return func(a *Act) {
+		a.dotEnvPaths = append(a.dotEnvPaths, paths...)
+	}
+}
+
+// WithWatchInterval is an option to change how often Watch checks config files for changes, default is a second.
+func WithWatchInterval(d time.Duration) Option {
+	return func(a *Act) {
+		a.watchInterval = d
+	}
+}
+
+// WithPOSIXFlags is an option to parse flags in GNU style, so long flags require double dash, i.e. "--port=80",
+// while single dash arguments are short flags set by "short" tag, which may be bundled, i.e. "-vp80" or "-vp 80".
+func WithPOSIXFlags() Option {
+	return func(a *Act) {
+		a.posix = true
+	}
+}
+
+// WithGroupedHelp is an option to print flags in the help output grouped by the nested structs, showing the short
+// alias, type, default value and environment variable of each flag in aligned columns. Descriptions are wrapped to
+// the width of the terminal set by COLUMNS environment variable.
+func WithGroupedHelp() Option {
+	return func(a *Act) {
+		a.groupedHelp = true
+	}
+}
+
+// WithSources is an option to replace the default chain of sources, which consists of environment variables,
+// config file if set and default values. Sources are listed in the order of precedence, while flags always take
+// precedence over all of them.
+func WithSources(sources ...Source) Option {
+	return func(a *Act) {
+		a.sources = sources
+	}
+}
+
+// WithDescription is an option to set command description shown in the list of subcommands.
+func WithDescription(description string) Option {
+	return func(a *Act) {
+		a.description = description
+	}
+}
+
+// WithHelpTemplate is an option to render the help output by the template, which receives *Help model of the
+// command, i.e. to add examples and links or to brand the output.
+func WithHelpTemplate(tmpl *template.Template) Option {
+	return func(a *Act) {
+		a.helpTemplate = tmpl
+	}
+}
+
+// WithExamples is an option to add examples of the command usage shown in the help output.
+func WithExamples(examples ...string) Option {
+	return func(a *Act) {
+		a.examples = append(a.examples, examples...)
+	}
+}
+
 // WithUsage allows to prefix your command name with a parent command name.
 func WithUsage(parentCmdName string) Option {
 	return func(a *Act) {
 		a.flagSet.Usage = func() {
 			fmt.Fprintf(a.output, "Usage of %s %s:\n", parentCmdName, a.name)
-			a.flagSet.PrintDefaults()
+			a.printDefaults()
 		}
 	}
 }
@@ -2,11 +2,17 @@ package act_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"flag"
+	"log"
+	"net"
 	"net/url"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -28,10 +34,10 @@ func TestParse_errors(t *testing.T) {
 		},
 		"unsupported-field-type": {
 			in: &struct {
-				Port int16
+				Port complex64
 			}{},
 			flags:   []string{""},
-			wantErr: "Port def: parsing value: type not supported: int16",
+			wantErr: "Port def: parsing value: type not supported: complex64",
 		},
 		"---help": {
 			in: &struct {
@@ -230,7 +236,7 @@ func TestParse_usage(t *testing.T) { //nolint:funlen
 					Port uint `def:"a"`
 				}
 			}{},
-			wantErr: `Port def: parsing uint "a": strconv.ParseUint: parsing "a": invalid syntax`,
+			wantErr: `DB.Port def: parsing uint "a": strconv.ParseUint: parsing "a": invalid syntax`,
 		},
 		"override-flag-and-env": {
 			config: &struct {
@@ -268,6 +274,29 @@ func TestParse_usage(t *testing.T) { //nolint:funlen
 			}{},
 			wantErr: `Start def: parsing time: parsing time "a" as "2006-01-02T15:04:05Z07:00": cannot parse "a" as "2006"`,
 		},
+		"required": {
+			config: &struct {
+				Password string `req:"true"`
+			}{},
+			want: "Usage of test: -password string password (env TEST_PASSWORD) (required)",
+		},
+		"text-unmarshaler-with-def": {
+			config: &struct {
+				Bind net.IP `def:"127.0.0.1"`
+			}{},
+			want: "Usage of test: -bind value bind (env TEST_BIND) (default 127.0.0.1)",
+		},
+		"named-types": {
+			config: &struct {
+				Mode    mode `def:"production"`
+				Verbose verbose
+				Retries int8
+			}{},
+			want: `Usage of test:
+-mode value mode (env TEST_MODE) (default production)
+-retries value retries (env TEST_RETRIES)
+-verbose verbose (env TEST_VERBOSE)`,
+		},
 		"time-valid-def": {
 			config: &struct {
 				Start act.Time `def:"2002-10-02T10:00:00-05:00"`
@@ -817,6 +846,1281 @@ func TestParse_environment(t *testing.T) { //nolint:cyclop,gocognit,funlen,maint
 	}
 }
 
+func TestParse_parseErrors(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	type config struct {
+		Port    uint `def:"a"`
+		Timeout time.Duration
+		DB      struct {
+			Hosts act.IntSlice
+			Port  int
+		}
+		Verbose bool
+	}
+
+	lookupEnvFunc := func(env string) (string, bool) {
+		switch env {
+		case "TEST_TIMEOUT":
+			return "b", true
+		case "TEST_DB_HOSTS":
+			return "1,c", true
+		}
+
+		return "", false
+	}
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(&bytes.Buffer{}),
+		act.WithLookupEnvFunc(lookupEnvFunc))
+
+	err := a.Parse(&config{}, []string{"-db-port", "d", "-verbose"}) //nolint:exhaustruct
+
+	var pe act.ParseErrors
+	if !errors.As(err, &pe) {
+		t.Fatalf("want ParseErrors got %T %v", err, err)
+	}
+
+	want := []struct {
+		path   string
+		source string
+		value  string
+	}{
+		{path: "Port", source: "def", value: "a"},
+		{path: "Timeout", source: "env", value: "b"},
+		{path: "DB.Hosts", source: "env", value: "1,c"},
+		{path: "DB.Port", source: "flag", value: "d"},
+	}
+
+	if len(pe) != len(want) {
+		t.Fatalf("want %d errors got %d: %v", len(want), len(pe), pe)
+	}
+
+	for i, w := range want {
+		if pe[i].Path != w.path || pe[i].Source != w.source || pe[i].Value != w.value || pe[i].Err == nil {
+			t.Errorf("want %s %s %q got %s %s %q", w.path, w.source, w.value, pe[i].Path, pe[i].Source, pe[i].Value)
+		}
+	}
+
+	var fe *act.FieldError
+	if !errors.As(err, &fe) || fe.Path != "Port" || fe.Flag != "port" || fe.Env != "TEST_PORT" {
+		t.Errorf("want first FieldError got %#v", fe)
+	}
+
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("want error %v got %v", strconv.ErrSyntax, err)
+	}
+
+	wantErr := `Port def: parsing uint "a": strconv.ParseUint: parsing "a": invalid syntax; ` +
+		`Timeout env: parsing duration "b": time: invalid duration "b"; ` +
+		`DB.Hosts env: parsing int: strconv.Atoi: parsing "c": invalid syntax; ` +
+		`DB.Port flag: invalid value "d" for flag -db-port: parse error`
+	if err.Error() != wantErr {
+		t.Errorf("want error %q got %q", wantErr, err.Error())
+	}
+}
+
+func TestParse_fieldErrors(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Host string `req:"true"`
+		Port uint   `req:"true"`
+	}
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(&bytes.Buffer{}))
+
+	err := a.Parse(&config{}, []string{"-port", "1"}) //nolint:exhaustruct
+
+	var pe act.ParseErrors
+	if !errors.As(err, &pe) || len(pe) != 1 {
+		t.Fatalf("want one ParseErrors got %T %v", err, err)
+	}
+
+	if !errors.Is(err, act.ErrRequired) || errors.Is(err, act.ErrUnknownKey) {
+		t.Errorf("want error %v got %v", act.ErrRequired, err)
+	}
+
+	fe := pe[0]
+	if fe.Path != "Host" || fe.Flag != "host" || fe.Env != "TEST_HOST" || fe.Source != "" {
+		t.Errorf("want missing Host got %#v", fe)
+	}
+
+	got, err := json.Marshal(fe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"path":"Host","flag":"host","env":"TEST_HOST","error":"required value not set (flag -host, env TEST_HOST)"}`
+	if string(got) != want {
+		t.Errorf("want json %s got %s", want, got)
+	}
+}
+
+func TestParse_secrets(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	type config struct {
+		DB struct {
+			Password string `def:"hunter2"`
+		}
+		APIToken     int
+		Key          string `def:"k3y" secret:"true"`
+		PasswordHint string `def:"pet name"`
+		TokenTTL     string `def:"1h"`
+		UserToken    string `def:"t0k3n" secret:"false"`
+		Port         int
+	}
+
+	lookupEnvFunc := func(env string) (string, bool) {
+		if env == "TEST_API_TOKEN" {
+			return "s3cr3t", true
+		}
+
+		return "", false
+	}
+
+	b := &bytes.Buffer{}
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b),
+		act.WithLookupEnvFunc(lookupEnvFunc))
+
+	err := a.Parse(&config{}, []string{}) //nolint:exhaustruct
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Fatalf("want error %v got %v", strconv.ErrSyntax, err)
+	}
+
+	want := `APIToken env: parsing int "******": strconv.Atoi: parsing "******": invalid syntax`
+	if err.Error() != want {
+		t.Errorf("want error %q got %q", want, err.Error())
+	}
+
+	var fe *act.FieldError
+	if !errors.As(err, &fe) || fe.Value != "******" {
+		t.Errorf("want masked value got %#v", fe)
+	}
+
+	a = act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b))
+
+	if err := a.Parse(&config{}, []string{"-h"}); err != nil { //nolint:exhaustruct
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"hunter2", "k3y"} {
+		if strings.Contains(b.String(), s) {
+			t.Errorf("want %q masked in usage got %q", s, b.String())
+		}
+	}
+
+	for _, s := range []string{`(default "pet name")`, `(default "1h")`, `(default "t0k3n")`} {
+		if !strings.Contains(b.String(), s) {
+			t.Errorf("want %q not masked in usage got %q", s, b.String())
+		}
+	}
+
+	a = act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b))
+
+	err = a.Parse(&config{}, []string{"-api-token", "s3cr3t", "-port", "p0rt"}) //nolint:exhaustruct
+	if want := `APIToken flag: invalid value "******" for flag -api-token: parse error; ` +
+		`Port flag: invalid value "p0rt" for flag -port: parse error`; err == nil || err.Error() != want {
+		t.Errorf("want error %q got %v", want, err)
+	}
+
+	if !errors.As(err, &fe) || fe.Value != "******" {
+		t.Errorf("want masked value got %#v", fe)
+	}
+
+	a = act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b))
+
+	if err := a.Parse(&config{}, []string{"-key", "k3y2"}); err != nil { //nolint:exhaustruct
+		t.Fatal(err)
+	}
+
+	for _, o := range a.Origins() {
+		if o.Path == "Key" && o.Value != "******" || o.Path == "DB.Password" && o.Value != "******" {
+			t.Errorf("want masked value got %#v", o)
+		}
+	}
+}
+
+func TestParse_required(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	type config struct {
+		Host string `req:"true" def:"localhost"`
+		DB   struct {
+			User     string `req:"true"`
+			Password string `req:"true" env:"DB_PASS"`
+		}
+	}
+
+	tests := map[string]struct {
+		flags   []string
+		env     map[string]string
+		wantErr string
+	}{
+		"all-missing": {
+			flags:   []string{},
+			env:     map[string]string{},
+			wantErr: "DB.User: required value not set (flag -db-user, env TEST_DB_USER); DB.Password: required value not set (flag -db-password, env DB_PASS)", //nolint:lll
+		},
+		"one-from-flag": {
+			flags:   []string{"-db-user", "foo"},
+			env:     map[string]string{},
+			wantErr: "DB.Password: required value not set (flag -db-password, env DB_PASS)",
+		},
+		"flag-and-env": {
+			flags:   []string{"-db-user", "foo"},
+			env:     map[string]string{"DB_PASS": "bar"},
+			wantErr: "",
+		},
+		"empty-env": {
+			flags:   []string{},
+			env:     map[string]string{"TEST_DB_USER": "", "DB_PASS": ""},
+			wantErr: "",
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			lookupEnvFunc := func(env string) (string, bool) {
+				v, ok := tt.env[env]
+
+				return v, ok
+			}
+
+			a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithLookupEnvFunc(lookupEnvFunc))
+
+			err := a.Parse(&config{}, tt.flags) //nolint:exhaustruct
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("want no error got %v", err)
+				}
+
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("want error %q got no error", tt.wantErr)
+			}
+
+			if !errors.Is(err, act.ErrRequired) {
+				t.Errorf("want ErrRequired got %v", err)
+			}
+
+			if err.Error() != tt.wantErr {
+				t.Errorf("want error %q got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+var errLevel = errors.New("invalid level")
+
+type level string
+
+func (l *level) Set(s string) error {
+	switch s {
+	case "debug", "info", "error":
+		*l = level(s)
+
+		return nil
+	}
+
+	return errLevel
+}
+
+func (l *level) String() string {
+	return string(*l)
+}
+
+type byteSize struct {
+	bytes uint64
+}
+
+func (b *byteSize) UnmarshalText(text []byte) error {
+	s := strings.TrimSuffix(string(text), "KB")
+
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if s != string(text) {
+		n *= 1024
+	}
+
+	b.bytes = n
+
+	return nil
+}
+
+func TestParse_values(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	type config struct {
+		Level   level    `def:"info"`
+		MaxBody byteSize `def:"1KB"`
+		IP      net.IP   `def:"127.0.0.1"`
+	}
+
+	tests := map[string]struct {
+		flags       []string
+		env         map[string]string
+		wantLevel   level
+		wantMaxBody uint64
+		wantIP      string
+		wantErr     string
+	}{
+		"def": {
+			flags:       []string{},
+			env:         map[string]string{},
+			wantLevel:   "info",
+			wantMaxBody: 1024,
+			wantIP:      "127.0.0.1",
+			wantErr:     "",
+		},
+		"env": {
+			flags:       []string{},
+			env:         map[string]string{"TEST_LEVEL": "error", "TEST_MAX_BODY": "2KB", "TEST_IP": "::1"},
+			wantLevel:   "error",
+			wantMaxBody: 2048,
+			wantIP:      "::1",
+			wantErr:     "",
+		},
+		"flags": {
+			flags:       []string{"-level", "debug", "-max-body", "10", "-ip", "10.0.0.1"},
+			env:         map[string]string{"TEST_LEVEL": "error"},
+			wantLevel:   "debug",
+			wantMaxBody: 10,
+			wantIP:      "10.0.0.1",
+			wantErr:     "",
+		},
+		"invalid-env": {
+			flags:   []string{},
+			env:     map[string]string{"TEST_LEVEL": "trace"},
+			wantErr: `Level env: parsing act_test.level "trace": invalid level`,
+		},
+		"invalid-text-env": {
+			flags:   []string{},
+			env:     map[string]string{"TEST_IP": "a"},
+			wantErr: `IP env: parsing net.IP "a": invalid IP address: a`,
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			lookupEnvFunc := func(env string) (string, bool) {
+				v, ok := tt.env[env]
+
+				return v, ok
+			}
+
+			a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithLookupEnvFunc(lookupEnvFunc))
+
+			cfg := &config{} //nolint:exhaustruct
+
+			err := a.Parse(cfg, tt.flags)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("want error %q got %v", tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if cfg.Level != tt.wantLevel {
+				t.Errorf("want level %q got %q", tt.wantLevel, cfg.Level)
+			}
+
+			if cfg.MaxBody.bytes != tt.wantMaxBody {
+				t.Errorf("want max body %d got %d", tt.wantMaxBody, cfg.MaxBody.bytes)
+			}
+
+			if cfg.IP.String() != tt.wantIP {
+				t.Errorf("want ip %q got %q", tt.wantIP, cfg.IP)
+			}
+		})
+	}
+}
+
+type (
+	mode    string
+	verbose bool
+	weight  float32
+)
+
+func TestParse_kinds(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	type config struct {
+		Int8    int8    `def:"-128"`
+		Int16   int16   `def:"32767"`
+		Int32   int32   `def:"-5"`
+		Uint8   uint8   `def:"255"`
+		Uint16  uint16  `def:"65535"`
+		Uint32  uint32  `def:"5"`
+		Float32 float32 `def:"1.5"`
+		Mode    mode    `def:"production"`
+		Verbose verbose
+		Weight  weight `def:"0.5"`
+	}
+
+	cfg := &config{} //nolint:exhaustruct
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithSources(act.DefSource()))
+
+	if err := a.Parse(cfg, []string{"-int-32", "7", "-mode", "development", "-verbose"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := &config{
+		Int8:    -128,
+		Int16:   32767,
+		Int32:   7,
+		Uint8:   255,
+		Uint16:  65535,
+		Uint32:  5,
+		Float32: 1.5,
+		Mode:    "development",
+		Verbose: true,
+		Weight:  0.5,
+	}
+
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("want %+v got %+v", want, cfg)
+	}
+}
+
+func TestParse_secretItems(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Ports  []int          `secret:"true"`
+		Limits map[string]int `secret:"true"`
+		Names  map[int]string `secret:"true"`
+	}
+
+	values := map[string]string{"TEST_PORTS": "1,abc", "TEST_LIMITS": "a=1,b=x9", "TEST_NAMES": "1=a,k3y=b"}
+
+	lookupEnvFunc := func(env string) (string, bool) {
+		v, ok := values[env]
+
+		return v, ok
+	}
+
+	err := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithLookupEnvFunc(lookupEnvFunc)).
+		Parse(&config{}, []string{}) //nolint:exhaustruct
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Fatalf("want error %v got %v", strconv.ErrSyntax, err)
+	}
+
+	want := `Ports env: parsing int "******": strconv.ParseInt: parsing "******": invalid syntax; ` +
+		`Limits env: parsing int "******": strconv.ParseInt: parsing "******": invalid syntax; ` +
+		`Names env: parsing int "******": strconv.ParseInt: parsing "******": invalid syntax`
+	if err.Error() != want {
+		t.Errorf("want error %q got %q", want, err.Error())
+	}
+}
+
+func TestParse_kinds_errors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		config  interface{}
+		args    []string
+		env     map[string]string
+		wantErr string
+	}{
+		"int8-overflow": {
+			config: &struct {
+				Int8 int8 `def:"128"`
+			}{},
+			args:    []string{},
+			env:     map[string]string{},
+			wantErr: `Int8 def: parsing int8 "128": value out of range [-128, 127]`,
+		},
+		"uint16-overflow": {
+			config: &struct {
+				Uint16 uint16 `def:"65536"`
+			}{},
+			args:    []string{},
+			env:     map[string]string{},
+			wantErr: `Uint16 def: parsing uint16 "65536": value out of range [0, 65535]`,
+		},
+		"uint32-negative": {
+			config: &struct {
+				Uint32 uint32 `def:"-1"`
+			}{},
+			args:    []string{},
+			env:     map[string]string{},
+			wantErr: `Uint32 def: parsing uint32 "-1": strconv.ParseUint: parsing "-1": invalid syntax`,
+		},
+		"float32-overflow": {
+			config: &struct {
+				Float32 float32 `def:"1e39"`
+			}{},
+			args:    []string{},
+			env:     map[string]string{},
+			wantErr: `Float32 def: parsing float32 "1e39": value out of range [-3.4028234663852886e+38, 3.4028234663852886e+38]`, //nolint:lll
+		},
+		"named-bool-invalid": {
+			config: &struct {
+				Verbose verbose `def:"a"`
+			}{},
+			args:    []string{},
+			env:     map[string]string{},
+			wantErr: `Verbose def: parsing act_test.verbose "a": strconv.ParseBool: parsing "a": invalid syntax`,
+		},
+		"int-flag-overflow": {
+			config: &struct {
+				Int int
+			}{},
+			args:    []string{"-int", "9223372036854775808"},
+			env:     map[string]string{},
+			wantErr: `Int flag: parsing int "9223372036854775808": value out of range [-9223372036854775808, 9223372036854775807]`, //nolint:lll
+		},
+		"uint-flag-negative": {
+			config: &struct {
+				Port uint
+			}{},
+			args:    []string{"-port", "-1"},
+			env:     map[string]string{},
+			wantErr: `Port flag: invalid value "-1" for flag -port: parse error`,
+		},
+		"bool-flag-invalid": {
+			config: &struct {
+				Debug bool
+			}{},
+			args:    []string{"-debug=maybe"},
+			env:     map[string]string{},
+			wantErr: `Debug flag: invalid value "maybe" for flag -debug: parse error`,
+		},
+		"int-env-overflow": {
+			config: &struct {
+				Int int
+			}{},
+			args:    []string{},
+			env:     map[string]string{"TEST_INT": "-9223372036854775809"},
+			wantErr: `Int env: parsing int "-9223372036854775809": value out of range [-9223372036854775808, 9223372036854775807]`, //nolint:lll
+		},
+		"uint64-flag-overflow": {
+			config: &struct {
+				Size uint64
+			}{},
+			args:    []string{"-size", "18446744073709551616"},
+			env:     map[string]string{},
+			wantErr: `Size flag: parsing uint64 "18446744073709551616": value out of range [0, 18446744073709551615]`,
+		},
+		"uint64-env-overflow": {
+			config: &struct {
+				Size uint64
+			}{},
+			args:    []string{},
+			env:     map[string]string{"TEST_SIZE": "18446744073709551616"},
+			wantErr: `Size env: parsing uint64 "18446744073709551616": value out of range [0, 18446744073709551615]`,
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			lookupEnvFunc := func(env string) (string, bool) {
+				v, ok := tt.env[env]
+
+				return v, ok
+			}
+
+			a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithLookupEnvFunc(lookupEnvFunc))
+
+			err := a.Parse(tt.config, tt.args)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("want error %q got %v", tt.wantErr, err)
+			}
+
+			if strings.Contains(n, "overflow") && !errors.Is(err, strconv.ErrRange) {
+				t.Errorf("want error %v got %v", strconv.ErrRange, err)
+			}
+		})
+	}
+}
+
+func TestParse_pointers(t *testing.T) { //nolint:funlen,cyclop
+	t.Parallel()
+
+	type tls struct {
+		Cert string `req:"true"`
+		Key  string
+	}
+
+	type config struct {
+		Retries *int
+		Toggle  *bool
+		Timeout *time.Duration `def:"1s"`
+		Bind    *net.IP
+		TLS     *tls
+		Cache   *struct {
+			Size uint `def:"10"`
+		}
+	}
+
+	tests := map[string]struct {
+		flags       []string
+		env         map[string]string
+		wantRetries *int
+		wantToggle  *bool
+		wantTLS     *tls
+		wantErr     string
+	}{
+		"unset": {
+			flags:       []string{},
+			env:         map[string]string{},
+			wantRetries: nil,
+			wantToggle:  nil,
+			wantTLS:     nil,
+			wantErr:     "",
+		},
+		"env": {
+			flags:       []string{},
+			env:         map[string]string{"TEST_RETRIES": "0", "TEST_TOGGLE": "false", "TEST_TLS_CERT": "cert.pem"},
+			wantRetries: intPtr(0),
+			wantToggle:  boolPtr(false),
+			wantTLS:     &tls{Cert: "cert.pem", Key: ""},
+			wantErr:     "",
+		},
+		"flags": {
+			flags:       []string{"-retries", "3", "-toggle", "-tls-cert", "cert.pem"},
+			env:         map[string]string{"TEST_RETRIES": "1"},
+			wantRetries: intPtr(3),
+			wantToggle:  boolPtr(true),
+			wantTLS:     &tls{Cert: "cert.pem", Key: ""},
+			wantErr:     "",
+		},
+		"optional-struct-required-field": {
+			flags:   []string{"-tls-key", "key.pem"},
+			env:     map[string]string{},
+			wantErr: "TLS.Cert: required value not set (flag -tls-cert, env TEST_TLS_CERT)",
+		},
+		"invalid-env": {
+			flags:   []string{},
+			env:     map[string]string{"TEST_RETRIES": "a"},
+			wantErr: `Retries env: parsing int "a": strconv.ParseInt: parsing "a": invalid syntax`,
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			lookupEnvFunc := func(env string) (string, bool) {
+				v, ok := tt.env[env]
+
+				return v, ok
+			}
+
+			a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithLookupEnvFunc(lookupEnvFunc))
+
+			cfg := &config{} //nolint:exhaustruct
+
+			err := a.Parse(cfg, tt.flags)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("want error %q got %v", tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(cfg.Retries, tt.wantRetries) {
+				t.Errorf("want retries %v got %v", tt.wantRetries, cfg.Retries)
+			}
+
+			if !reflect.DeepEqual(cfg.Toggle, tt.wantToggle) {
+				t.Errorf("want toggle %v got %v", tt.wantToggle, cfg.Toggle)
+			}
+
+			if !reflect.DeepEqual(cfg.TLS, tt.wantTLS) {
+				t.Errorf("want tls %v got %v", tt.wantTLS, cfg.TLS)
+			}
+
+			if cfg.Timeout == nil || *cfg.Timeout != time.Second {
+				t.Errorf("want timeout 1s got %v", cfg.Timeout)
+			}
+
+			if cfg.Bind != nil {
+				t.Errorf("want bind nil got %v", cfg.Bind)
+			}
+
+			if cfg.Cache == nil || cfg.Cache.Size != 10 {
+				t.Errorf("want cache size 10 got %v", cfg.Cache)
+			}
+		})
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestParse_slices(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	type config struct {
+		Hosts    []string `def:"a,b"`
+		Ports    []int
+		Ratios   []float64 `sep:";"`
+		Timeouts []time.Duration
+		Flags    []bool
+		Addrs    []net.IP
+		Levels   []level
+		Names    []string `sep:";"`
+	}
+
+	path := writeFile(t, "config.json", `{"names": ["a,b", "c"]}`)
+
+	tests := map[string]struct {
+		args []string
+		env  map[string]string
+		want config
+	}{
+		"sources": {
+			args: []string{},
+			env:  map[string]string{"TEST_PORTS": "1,2", "TEST_LEVELS": "debug,info"},
+			want: config{
+				Hosts: []string{"a", "b"}, Ports: []int{1, 2}, Ratios: nil, Timeouts: nil, Flags: nil, Addrs: nil,
+				Levels: []level{"debug", "info"}, Names: []string{"a,b", "c"},
+			},
+		},
+		"first-flag-replaces-and-repeated-append": {
+			args: []string{"-hosts", "c", "-hosts", "d,e", "-ports", "3", "-ports", "4", "-names", "d"},
+			env:  map[string]string{"TEST_PORTS": "1,2"},
+			want: config{
+				Hosts: []string{"c", "d", "e"}, Ports: []int{3, 4}, Ratios: nil, Timeouts: nil, Flags: nil, Addrs: nil,
+				Levels: nil, Names: []string{"d"},
+			},
+		},
+		"element-types": {
+			args: []string{
+				"-ratios", "0.5;1.5", "-timeouts", "1s,2m", "-flags", "true,false", "-addrs", "127.0.0.1", "-addrs", "::1",
+			},
+			env: map[string]string{},
+			want: config{
+				Hosts: []string{"a", "b"}, Ports: nil, Ratios: []float64{0.5, 1.5},
+				Timeouts: []time.Duration{time.Second, 2 * time.Minute}, Flags: []bool{true, false},
+				Addrs: []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")}, Levels: nil, Names: []string{"a,b", "c"},
+			},
+		},
+		"empty-flag": {
+			args: []string{"-hosts="},
+			env:  map[string]string{},
+			want: config{
+				Hosts: nil, Ports: nil, Ratios: nil, Timeouts: nil, Flags: nil, Addrs: nil, Levels: nil,
+				Names: []string{"a,b", "c"},
+			},
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			lookupEnvFunc := func(env string) (string, bool) {
+				v, ok := tt.env[env]
+
+				return v, ok
+			}
+
+			a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithLookupEnvFunc(lookupEnvFunc),
+				act.WithConfigFile(path))
+
+			cfg := &config{} //nolint:exhaustruct
+
+			if err := a.Parse(cfg, tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(*cfg, tt.want) {
+				t.Errorf("\ngot  %#v\nwant %#v", *cfg, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_slices_errors(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b))
+
+	err := a.Parse(&struct { //nolint:exhaustruct
+		Ports []int `def:"1,a"`
+		Hosts []string
+	}{}, []string{"-hosts", "a", "-h"})
+
+	if want := `Ports def: parsing int "a": strconv.ParseInt: parsing "a": invalid syntax`; err == nil ||
+		err.Error() != want {
+		t.Errorf("want error %q got %v", want, err)
+	}
+
+	a = act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b))
+
+	if err := a.Parse(&struct { //nolint:exhaustruct
+		Hosts []string `def:"a;b" sep:";"`
+	}{}, []string{"-h"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(b.String(), "(default a;b)") {
+		t.Errorf("want default in usage got %q", b.String())
+	}
+}
+
+func TestParse_maps(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	type config struct {
+		Headers  map[string]string
+		Quotas   map[string]int           `def:"a=1,b=2"`
+		Timeouts map[string]time.Duration `sep:";"`
+		Limits   map[int]float64
+	}
+
+	path := writeFile(t, "config.json", `{"limits": {"1": 0.5, "2": 1.5}}`)
+
+	tests := map[string]struct {
+		args    []string
+		env     map[string]string
+		environ []string
+		want    config
+	}{
+		"sources": {
+			args:    []string{},
+			env:     map[string]string{"TEST_HEADERS": "X=1,Y=a=b"},
+			environ: []string{},
+			want: config{
+				Headers: map[string]string{"X": "1", "Y": "a=b"}, Quotas: map[string]int{"a": 1, "b": 2},
+				Timeouts: nil, Limits: map[int]float64{1: 0.5, 2: 1.5},
+			},
+		},
+		"env-family": {
+			args: []string{},
+			env: map[string]string{
+				"TEST_HEADERS_X_REQUEST_ID": "1", "TEST_HEADERS_ACCEPT": "text/plain", "TEST_QUOTAS_C": "3",
+			},
+			environ: []string{
+				"TEST_HEADERS_X_REQUEST_ID=1", "TEST_HEADERS_ACCEPT=text/plain", "TEST_HEADERS_NOT_IN_LOOKUP=1",
+				"TEST_HEADERS_=1", "TEST_QUOTAS_C=3", "HOME=/root",
+			},
+			want: config{
+				Headers:  map[string]string{"X_REQUEST_ID": "1", "ACCEPT": "text/plain"},
+				Quotas:   map[string]int{"C": 3},
+				Timeouts: nil, Limits: map[int]float64{1: 0.5, 2: 1.5},
+			},
+		},
+		"repeated-flags": {
+			args: []string{"-headers", "a=1", "-headers", "b=2,c=3", "-quotas", "c=3", "-timeouts", "a=1s;b=2m"},
+			env:  map[string]string{"TEST_HEADERS": "X=1"},
+			want: config{
+				Headers: map[string]string{"a": "1", "b": "2", "c": "3"}, Quotas: map[string]int{"c": 3},
+				Timeouts: map[string]time.Duration{"a": time.Second, "b": 2 * time.Minute},
+				Limits:   map[int]float64{1: 0.5, 2: 1.5},
+			},
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			lookupEnvFunc := func(env string) (string, bool) {
+				v, ok := tt.env[env]
+
+				return v, ok
+			}
+
+			a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithLookupEnvFunc(lookupEnvFunc),
+				act.WithEnvironFunc(func() []string { return tt.environ }), act.WithConfigFile(path))
+
+			cfg := &config{} //nolint:exhaustruct
+
+			if err := a.Parse(cfg, tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(*cfg, tt.want) {
+				t.Errorf("\ngot  %#v\nwant %#v", *cfg, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_maps_siblings(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Headers        map[string]string
+		HeadersTimeout int
+		HeadersExtra   map[string]string
+	}
+
+	env := map[string]string{"TEST_HEADERS_TIMEOUT": "5", "TEST_HEADERS_EXTRA_A": "1", "TEST_HEADERS_B": "2"}
+	dotEnv := writeFile(t, ".env", "TEST_HEADERS_TIMEOUT=5\nTEST_HEADERS_EXTRA_A=1\nTEST_HEADERS_B=2\n")
+
+	tests := map[string]struct {
+		env  map[string]string
+		opts []act.Option
+	}{
+		"env":    {env: env, opts: nil},
+		"dotenv": {env: map[string]string{}, opts: []act.Option{act.WithDotEnv(dotEnv)}},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			lookupEnvFunc := func(env string) (string, bool) {
+				v, ok := tt.env[env]
+
+				return v, ok
+			}
+
+			environ := make([]string, 0, len(tt.env))
+			for k, v := range tt.env {
+				environ = append(environ, k+"="+v)
+			}
+
+			opts := append([]act.Option{
+				act.WithErrorHandling(flag.ContinueOnError), act.WithLookupEnvFunc(lookupEnvFunc),
+				act.WithEnvironFunc(func() []string { return environ }),
+			}, tt.opts...)
+
+			cfg := &config{} //nolint:exhaustruct
+
+			if err := act.New("test", opts...).Parse(cfg, []string{}); err != nil {
+				t.Fatal(err)
+			}
+
+			want := config{
+				Headers: map[string]string{"B": "2"}, HeadersTimeout: 5, HeadersExtra: map[string]string{"A": "1"},
+			}
+
+			if !reflect.DeepEqual(*cfg, want) {
+				t.Errorf("\ngot  %#v\nwant %#v", *cfg, want)
+			}
+		})
+	}
+}
+
+func TestParse_maps_errors(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Quotas map[string]int `def:"a=1,b=2"`
+	}
+
+	b := &bytes.Buffer{}
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b))
+
+	err := a.Parse(&config{}, []string{"-quotas", "a", "-quotas", "b=x"}) //nolint:exhaustruct
+	if !errors.Is(err, act.ErrInvalidMapItem) || !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("want errors %v and %v got %v", act.ErrInvalidMapItem, strconv.ErrSyntax, err)
+	}
+
+	a = act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b))
+
+	if err := a.Parse(&config{}, []string{"-h"}); err != nil { //nolint:exhaustruct
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(b.String(), "(default a=1,b=2)") {
+		t.Errorf("want default in usage got %q", b.String())
+	}
+}
+
+type backend struct {
+	Host   string `req:"true"`
+	Port   int    `def:"80"`
+	Weight int    `def:"1" flag:"weight" env:"WEIGHT"`
+}
+
+func TestParse_structSlices(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	type config struct {
+		Backends []backend
+		Name     string
+	}
+
+	tests := map[string]struct {
+		args    []string
+		env     map[string]string
+		file    string
+		want    []backend
+		wantErr string
+	}{
+		"none": {
+			args: []string{"-name", "a"},
+			env:  map[string]string{},
+			file: `{}`,
+			want: nil,
+		},
+		"env": {
+			args: []string{},
+			env: map[string]string{
+				"TEST_BACKENDS_0_HOST": "a", "TEST_BACKENDS_1_HOST": "b", "TEST_BACKENDS_1_PORT": "8080",
+				"TEST_BACKENDS_TIMEOUT": "5",
+			},
+			file: `{}`,
+			want: []backend{{Host: "a", Port: 80, Weight: 1}, {Host: "b", Port: 8080, Weight: 1}},
+		},
+		"flags-override-env": {
+			args: []string{"-backends-0-host", "b", "-backends-1-host=c", "-backends-1-weight", "2"},
+			env:  map[string]string{"TEST_BACKENDS_0_HOST": "a"},
+			file: `{}`,
+			want: []backend{{Host: "b", Port: 80, Weight: 1}, {Host: "c", Port: 80, Weight: 2}},
+		},
+		"file": {
+			args: []string{},
+			env:  map[string]string{},
+			file: `{"backends": [{"host": "a"}, {"host": "b", "weight": 3}]}`,
+			want: []backend{{Host: "a", Port: 80, Weight: 1}, {Host: "b", Port: 80, Weight: 3}},
+		},
+		"env-gap": {
+			args:    []string{},
+			env:     map[string]string{"TEST_BACKENDS_0_HOST": "h0", "TEST_BACKENDS_2_HOST": "h2"},
+			file:    `{}`,
+			wantErr: "TEST_BACKENDS_2_HOST env: unknown config key",
+		},
+		"required": {
+			args:    []string{"-backends-0-host", "a", "-backends-1-port", "81"},
+			env:     map[string]string{},
+			file:    `{}`,
+			wantErr: "Backends.1.Host: required value not set (flag -backends-1-host, env TEST_BACKENDS_1_HOST)",
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			lookupEnvFunc := func(env string) (string, bool) {
+				v, ok := tt.env[env]
+
+				return v, ok
+			}
+
+			environ := make([]string, 0, len(tt.env))
+			for k, v := range tt.env {
+				environ = append(environ, k+"="+v)
+			}
+
+			a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithLookupEnvFunc(lookupEnvFunc),
+				act.WithEnvironFunc(func() []string { return environ }),
+				act.WithConfigFile(writeFile(t, "config.json", tt.file)))
+
+			cfg := &config{} //nolint:exhaustruct
+
+			err := a.Parse(cfg, tt.args)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("want error %q got %v", tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(cfg.Backends, tt.want) {
+				t.Errorf("\ngot  %#v\nwant %#v", cfg.Backends, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_structSlices_usage(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b))
+
+	cfg := &struct{ Backends []backend }{} //nolint:exhaustruct
+
+	if err := a.Parse(cfg, []string{"-h"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"-backends-0-host", "TEST_BACKENDS_0_WEIGHT"} {
+		if !strings.Contains(b.String(), s) {
+			t.Errorf("want %s in usage got %q", s, b.String())
+		}
+	}
+
+	if cfg.Backends != nil {
+		t.Errorf("want no backends got %v", cfg.Backends)
+	}
+}
+
+type CommonConfig struct {
+	LogLevel string `def:"info"`
+	Port     int    `def:"80"`
+}
+
+type TLSConfig struct {
+	Cert string
+}
+
+func TestParse_embedded(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		CommonConfig
+		Name string
+		DB   struct {
+			*TLSConfig
+			Host string
+		}
+	}
+
+	lookupEnvFunc := func(env string) (string, bool) {
+		if env == "TEST_LOG_LEVEL" {
+			return "debug", true
+		}
+
+		return "", false
+	}
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithLookupEnvFunc(lookupEnvFunc))
+
+	cfg := &config{} //nolint:exhaustruct
+
+	if err := a.Parse(cfg, []string{"-port", "8080", "-db-cert", "cert.pem"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.LogLevel != "debug" || cfg.Port != 8080 || cfg.DB.TLSConfig == nil || cfg.DB.Cert != "cert.pem" {
+		t.Errorf("want embedded fields set got %+v", cfg)
+	}
+
+	paths := make([]string, 0, len(a.Origins()))
+	for _, o := range a.Origins() {
+		paths = append(paths, o.Path)
+	}
+
+	if want := []string{"LogLevel", "Port", "Name", "DB.Cert", "DB.Host"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("want paths %v got %v", want, paths)
+	}
+}
+
+func TestParse_embedded_duplicate(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		CommonConfig
+		Port  uint
+		Level string `flag:"log-level"`
+	}
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError))
+
+	err := a.Parse(&config{}, []string{}) //nolint:exhaustruct
+	if !errors.Is(err, act.ErrDuplicateField) {
+		t.Fatalf("want error %v got %v", act.ErrDuplicateField, err)
+	}
+
+	if want := "Port: duplicate field (flag -port); Level: duplicate field (flag -log-level)"; err.Error() != want {
+		t.Errorf("want error %q got %q", want, err.Error())
+	}
+}
+
+type commonConfig struct {
+	Region string `def:"eu"`
+}
+
+func TestParse_ignored(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		commonConfig
+		Host    string          `def:"localhost"`
+		Logger  *log.Logger     `act:"-"`
+		Client  complex64       `act:"-"`
+		Runtime struct{ N int } `act:"-"`
+		mu      sync.Mutex
+		counter int
+	}
+
+	b := &bytes.Buffer{}
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b))
+
+	logger := log.New(b, "", 0)
+	cfg := &config{Logger: logger, counter: 1} //nolint:exhaustruct
+
+	if err := a.Parse(cfg, []string{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Host != "localhost" || cfg.Logger != logger || cfg.counter != 1 || cfg.Region != "" {
+		t.Errorf("want only exported fields set got %+v", cfg)
+	}
+
+	if o := a.Origins(); len(o) != 1 || o[0].Path != "Host" {
+		t.Errorf("want only Host field got %v", o)
+	}
+
+	a = act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b))
+
+	if err := a.Parse(cfg, []string{"-logger", "x"}); err == nil || err.Error() != "flag provided but not defined: -logger" {
+		t.Errorf("want undefined flag error got %v", err)
+	}
+}
+
 func TestWithUsage(t *testing.T) {
 	t.Parallel()
 
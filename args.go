@@ -0,0 +1,214 @@
+package act
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/iancoleman/strcase"
+)
+
+// argMeta holds the metadata of a positional argument, i.e. field tagged by `arg:"0"` or `arg:"rest"`.
+type argMeta struct {
+	field *fieldMeta
+	index int // index is -1 for the rest of the arguments.
+}
+
+// parseArg registers the field bound to the positional argument and sets its default value if any.
+func (a *Act) parseArg(sf reflect.StructField, v reflect.Value, prefix string) { //nolint:cyclop
+	f := &fieldMeta{
+		Field: Field{
+			Path: a.path(sf, prefix),
+			Flag: "",
+			Env:  "",
+			Tag:  sf.Tag,
+			Type: sf.Type,
+		},
+		source:   "",
+		location: "",
+		value:    "",
+		arg:      strcase.ToScreamingSnake(sf.Name),
+		short:    "",
+		help:     a.helpText(sf, ""),
+		rv:       v,
+		required: false,
+		secret:   a.secret(sf),
+		absent:   false,
+		set:      false,
+	}
+
+	tag := sf.Tag.Get("arg")
+	index := -1
+
+	if tag != "rest" {
+		i, err := strconv.Atoi(tag)
+		if err != nil || i < 0 {
+			a.errs = append(a.errs, f.error("", "", "", fmt.Errorf("%w: tag %q", ErrInvalidArgument, tag)))
+
+			return
+		}
+
+		index = i
+	}
+
+	switch {
+	case index == -1 && !isSliceType(sf.Type):
+		a.errs = append(a.errs, f.error("", "", "", fmt.Errorf("%w: rest requires slice", ErrInvalidArgument)))
+
+		return
+	case !isSliceType(sf.Type) && !isSupported(sf.Type):
+		a.errs = append(a.errs, f.error("", "", "", fmt.Errorf("parsing value: %w: %v", ErrUnsupportedType, sf.Type)))
+
+		return
+	case a.duplicate(f.Field):
+		a.errs = append(a.errs, f.error("", "", "", fmt.Errorf("%w (argument %s)", ErrDuplicateField, f.arg)))
+
+		return
+	}
+
+	for _, arg := range a.args {
+		if arg.index == index {
+			a.errs = append(a.errs, f.error("", "", "", fmt.Errorf("%w: duplicate tag %q", ErrInvalidArgument, tag)))
+
+			return
+		}
+	}
+
+	def, hasDef := sf.Tag.Lookup("def")
+
+	// Indexed arguments are required unless they have default value or are pointers, while the rest of the
+	// arguments are required only by req tag.
+	f.required = sf.Tag.Get("req") == "true" || (index != -1 && !hasDef && sf.Type.Kind() != reflect.Ptr)
+
+	a.fields = append(a.fields, f)
+	a.args = append(a.args, &argMeta{field: f, index: index})
+
+	sort.SliceStable(a.args, func(i, j int) bool {
+		return uint(a.args[i].index) < uint(a.args[j].index) // The rest goes last.
+	})
+
+	if def == "" {
+		return
+	}
+
+	values := []string{def}
+	if isSliceType(sf.Type) {
+		values = strings.Split(def, separator(sf.Tag))
+	}
+
+	if err := setArg(f, values); err != nil {
+		a.errs = append(a.errs, f.error("def", "", def, err))
+
+		return
+	}
+
+	f.set = true
+	f.source = "def"
+	f.value = def
+}
+
+// bindArgs sets the positional arguments remaining after the flags to the fields bound to them. Missing arguments
+// are reported by checkRequired.
+func (a *Act) bindArgs() ParseErrors {
+	if a.help {
+		return nil
+	}
+
+	var errs ParseErrors
+
+	args := a.flagSet.Args()
+	next := 0
+
+	for _, arg := range a.args {
+		var values []string
+
+		switch {
+		case arg.index == -1 && next < len(args):
+			values = args[next:]
+		case arg.index != -1 && arg.index < len(args):
+			values = args[arg.index : arg.index+1]
+		}
+
+		if arg.index != -1 {
+			next = arg.index + 1
+		}
+
+		if len(values) == 0 {
+			continue
+		}
+
+		value := strings.Join(values, separator(arg.field.Tag))
+
+		if err := setArg(arg.field, values); err != nil {
+			errs = append(errs, arg.field.error("arg", "", value, err))
+
+			continue
+		}
+
+		arg.field.set = true
+		arg.field.source = "arg"
+		arg.field.location = ""
+		arg.field.value = value
+	}
+
+	return errs
+}
+
+// setArg sets the values to the field, one value to the scalar field or each value as an element of the slice.
+func setArg(f *fieldMeta, values []string) error {
+	if !isSliceType(f.Type) {
+		return setValue(f.rv, values[0])
+	}
+
+	s := reflect.MakeSlice(f.Type, 0, len(values))
+
+	for _, value := range values {
+		e := reflect.New(f.Type.Elem()).Elem()
+
+		if err := setValue(e, value); err != nil {
+			return err
+		}
+
+		s = reflect.Append(s, e)
+	}
+
+	f.rv.Set(s)
+
+	return nil
+}
+
+// argsUsage formats positional arguments for the usage line, i.e. "SRC [DST] [FILES...]".
+func (a *Act) argsUsage() string {
+	names := make([]string, 0, len(a.args))
+
+	for _, arg := range a.args {
+		name := arg.field.arg
+		if arg.index == -1 {
+			name += "..."
+		}
+
+		if !arg.field.required {
+			name = fmt.Sprintf("[%s]", name)
+		}
+
+		names = append(names, name)
+	}
+
+	return strings.Join(names, " ")
+}
+
+// printArgs prints the list of positional arguments.
+func (a *Act) printArgs() {
+	fmt.Fprintf(a.output, "\nArguments:\n")
+
+	w := tabwriter.NewWriter(a.output, 0, 0, 2, ' ', 0) //nolint:gomnd
+
+	for _, arg := range a.args {
+		fmt.Fprintf(w, "  %s\t%s\n", arg.field.arg, arg.field.help)
+	}
+
+	_ = w.Flush()
+}
@@ -0,0 +1,199 @@
+package act_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.ectobit.com/act"
+)
+
+type copyConfig struct {
+	Verbose bool
+	Src     string        `arg:"0" help:"source file"`
+	Dst     string        `arg:"1" def:"."`
+	Timeout time.Duration `arg:"2" def:"1s"`
+	Files   []string      `arg:"rest"`
+}
+
+func TestParse_args(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args []string
+		want *copyConfig
+	}{
+		"all": {
+			args: []string{"-verbose", "a", "b", "5s", "c", "d"},
+			want: &copyConfig{Verbose: true, Src: "a", Dst: "b", Timeout: 5 * time.Second, Files: []string{"c", "d"}},
+		},
+		"defaults": {
+			args: []string{"a"},
+			want: &copyConfig{Verbose: false, Src: "a", Dst: ".", Timeout: time.Second, Files: nil},
+		},
+		"terminator": {
+			args: []string{"--", "-a", "b"},
+			want: &copyConfig{Verbose: false, Src: "-a", Dst: "b", Timeout: time.Second, Files: nil},
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			a := act.New("test", act.WithErrorHandling(flag.ContinueOnError))
+
+			cfg := &copyConfig{} //nolint:exhaustruct
+
+			if err := a.Parse(cfg, tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(cfg, tt.want) {
+				t.Errorf("\ngot  %+v\nwant %+v", cfg, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_argsErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		config    interface{}
+		args      []string
+		wantErr   string
+		wantErrIs error
+	}{
+		"missing": {
+			config:    &copyConfig{}, //nolint:exhaustruct
+			args:      []string{"-verbose"},
+			wantErr:   "Src: required value not set (argument SRC)",
+			wantErrIs: act.ErrRequired,
+		},
+		"invalid": {
+			config:    &copyConfig{}, //nolint:exhaustruct
+			args:      []string{"a", "b", "c"},
+			wantErr:   `Timeout arg: parsing duration "c": time: invalid duration "c"`,
+			wantErrIs: nil,
+		},
+		"invalid-rest": {
+			config: &struct {
+				Ports []uint16 `arg:"rest"`
+			}{},
+			args:      []string{"80", "x"},
+			wantErr:   `Ports arg: parsing uint16 "x": strconv.ParseUint: parsing "x": invalid syntax`,
+			wantErrIs: nil,
+		},
+		"invalid-tag": {
+			config: &struct {
+				Src string `arg:"first"`
+			}{},
+			args:      []string{"a"},
+			wantErr:   `Src: invalid positional argument: tag "first"`,
+			wantErrIs: act.ErrInvalidArgument,
+		},
+		"duplicate-tag": {
+			config: &struct {
+				Src string `arg:"0"`
+				Dst string `arg:"0"`
+			}{},
+			args:      []string{"a"},
+			wantErr:   `Dst: invalid positional argument: duplicate tag "0"`,
+			wantErrIs: act.ErrInvalidArgument,
+		},
+		"rest-not-slice": {
+			config: &struct {
+				Files string `arg:"rest"`
+			}{},
+			args:      []string{"a"},
+			wantErr:   "Files: invalid positional argument: rest requires slice",
+			wantErrIs: act.ErrInvalidArgument,
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			a := act.New("test", act.WithErrorHandling(flag.ContinueOnError))
+
+			err := a.Parse(tt.config, tt.args)
+			if err == nil {
+				t.Fatal("want error got no error")
+			}
+
+			if err.Error() != tt.wantErr {
+				t.Errorf("want error %q got %q", tt.wantErr, err.Error())
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("want error %v got %v", tt.wantErrIs, err)
+			}
+		})
+	}
+}
+
+func TestParse_argsUsage(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	a := act.New("mycmd", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b))
+
+	if err := a.Parse(&copyConfig{}, []string{"-h"}); err != nil { //nolint:exhaustruct
+		t.Fatal(err)
+	}
+
+	want := `Usage: mycmd [flags] SRC [DST] [TIMEOUT] [FILES...]
+  -verbose
+    	verbose (env MYCMD_VERBOSE)
+
+Arguments:
+  SRC      source file
+  DST      dst
+  TIMEOUT  timeout
+  FILES    files
+`
+
+	if b.String() != want {
+		t.Errorf("want\n%s\ngot\n%s", want, b.String())
+	}
+}
+
+func TestRun_args(t *testing.T) {
+	t.Parallel()
+
+	a := act.New("app", act.WithErrorHandling(flag.ContinueOnError))
+
+	cfg := &copyConfig{} //nolint:exhaustruct
+
+	var got []string
+
+	a.Command("copy", cfg, func(args []string) error {
+		got = args
+
+		return nil
+	})
+
+	if err := a.Run(nil, []string{"copy", "-verbose", "a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Src != "a" || cfg.Dst != "b" || !cfg.Verbose {
+		t.Errorf("want src a and dst b got %+v", cfg)
+	}
+
+	if !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("want handler args [a b] got %v", got)
+	}
+}
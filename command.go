@@ -0,0 +1,165 @@
+package act
+
+import (
+	"flag"
+	"fmt"
+	"text/tabwriter"
+)
+
+// Handler is executed when its command is selected, receiving the remaining positional arguments.
+type Handler func(args []string) error
+
+// Command adds a subcommand and returns it, so it may have subcommands of its own. Config, if not nil, has to be
+// a pointer to struct populated when the command is selected. Flags of the parent commands are available to the
+// subcommand as well, while names of environment variables are prefixed by the parent command names, i.e.
+// APP_DB_MIGRATE_STEPS. Output, error handling, environment lookup function, dotenv files, POSIX flags mode, grouped
+// help and help template are inherited from the parent, but sources and config file are not.
+func (a *Act) Command(name string, config interface{}, handler Handler, opts ...Option) *Act {
+	inherit := func(c *Act) {
+		c.parent = a
+		c.config = config
+		c.handler = handler
+		c.output = a.output
+		c.lookupEnvFunc = a.lookupEnvFunc
+		c.environFunc = a.environFunc
+		c.dotEnvPaths = a.dotEnvPaths
+		c.errorHandling = a.errorHandling
+		c.posix = a.posix
+		c.groupedHelp = a.groupedHelp
+		c.helpTemplate = a.helpTemplate
+		c.flagSet.Init(fmt.Sprintf("%s %s", a.flagSet.Name(), name), flag.ContinueOnError)
+	}
+
+	c := New(name, append([]Option{inherit}, opts...)...)
+
+	a.commands = append(a.commands, c)
+
+	return c
+}
+
+// Run parses command line flags, environment variables and default values of the command tree and executes the
+// handler of the command selected by the positional arguments, i.e. "app db migrate". Supplied config is populated
+// with the values of the top level command.
+func (a *Act) Run(config interface{}, args []string) error {
+	return a.exit(a.run(config, args))
+}
+
+func (a *Act) run(config interface{}, args []string) error {
+	if config == nil {
+		config = &struct{}{}
+	}
+
+	if err := a.parseFlags(config, args); err != nil {
+		return err
+	}
+
+	rest := a.flagSet.Args()
+
+	if len(a.commands) == 0 || (len(rest) == 0 && a.handler != nil) {
+		if errs := a.bindArgs(); len(errs) > 0 {
+			return errs
+		}
+
+		for c := a; c != nil; c = c.parent {
+			if err := c.finish(); err != nil {
+				return err
+			}
+		}
+
+		if a.handler == nil {
+			return nil
+		}
+
+		return a.handler(rest)
+	}
+
+	if len(rest) == 0 {
+		a.flagSet.Usage()
+
+		return ErrNoCommand
+	}
+
+	c := a.command(rest[0])
+	if c == nil {
+		a.flagSet.Usage()
+
+		return fmt.Errorf("%w: %s", ErrUnknownCommand, rest[0])
+	}
+
+	return c.run(c.config, rest[1:])
+}
+
+// command returns subcommand by its name.
+func (a *Act) command(name string) *Act {
+	for _, c := range a.commands {
+		if c.name == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// inheritFlags adds flags of the parent commands unless overridden by the command's own flags.
+func (a *Act) inheritFlags() {
+	if a.parent == nil {
+		return
+	}
+
+	a.parent.flagSet.VisitAll(func(f *flag.Flag) {
+		if a.flagSet.Lookup(f.Name) != nil {
+			return
+		}
+
+		a.flagSet.Var(unwrapValue(f.Value), f.Name, f.Usage)
+		a.flagSet.Lookup(f.Name).DefValue = f.DefValue
+	})
+}
+
+// printUsage prints flags defaults followed by the lists of positional arguments, subcommands and examples, unless
+// the help template is set.
+func (a *Act) printUsage() {
+	if a.helpTemplate != nil {
+		if err := a.helpTemplate.Execute(a.output, a.helpModel()); err != nil {
+			fmt.Fprintf(a.output, "act: executing help template: %v\n", err)
+		}
+
+		return
+	}
+
+	if len(a.args) > 0 {
+		fmt.Fprintf(a.output, "Usage: %s [flags] %s\n", a.flagSet.Name(), a.argsUsage())
+	} else {
+		fmt.Fprintf(a.output, "Usage of %s:\n", a.flagSet.Name())
+	}
+
+	if a.groupedHelp {
+		a.printHelp()
+	} else {
+		a.printDefaults()
+	}
+
+	if len(a.args) > 0 {
+		a.printArgs()
+	}
+
+	if len(a.commands) > 0 {
+		fmt.Fprintf(a.output, "\nCommands:\n")
+
+		w := tabwriter.NewWriter(a.output, 0, 0, 2, ' ', 0) //nolint:gomnd
+
+		for _, c := range a.commands {
+			fmt.Fprintf(w, "  %s\t%s\n", c.name, c.description)
+		}
+
+		_ = w.Flush()
+	}
+
+	if len(a.examples) > 0 {
+		fmt.Fprintf(a.output, "\nExamples:\n")
+
+		for _, e := range a.examples {
+			fmt.Fprintf(a.output, "  %s\n", e)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package act_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go.ectobit.com/act"
+)
+
+type globalConfig struct {
+	Verbose bool
+	DSN     string `req:"true"`
+}
+
+type migrateConfig struct {
+	Steps int `def:"1"`
+}
+
+func TestRun(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	tests := map[string]struct {
+		args        []string
+		env         map[string]string
+		wantCalled  string
+		wantArgs    []string
+		wantVerbose bool
+		wantSteps   int
+		wantErr     error
+	}{
+		"global-flags-before-command": {
+			args:        []string{"-verbose", "-dsn", "postgres://", "db", "migrate", "-steps", "3", "up"},
+			env:         map[string]string{},
+			wantCalled:  "migrate",
+			wantArgs:    []string{"up"},
+			wantVerbose: true,
+			wantSteps:   3,
+			wantErr:     nil,
+		},
+		"global-flags-after-command": {
+			args:        []string{"db", "migrate", "-verbose", "-dsn", "postgres://"},
+			env:         map[string]string{},
+			wantCalled:  "migrate",
+			wantArgs:    []string{},
+			wantVerbose: true,
+			wantSteps:   1,
+			wantErr:     nil,
+		},
+		"chained-env-prefix": {
+			args:        []string{"db", "migrate"},
+			env:         map[string]string{"APP_DSN": "postgres://", "APP_DB_MIGRATE_STEPS": "5"},
+			wantCalled:  "migrate",
+			wantArgs:    []string{},
+			wantVerbose: false,
+			wantSteps:   5,
+			wantErr:     nil,
+		},
+		"sibling-command": {
+			args:        []string{"-dsn", "postgres://", "serve", "foo"},
+			env:         map[string]string{},
+			wantCalled:  "serve",
+			wantArgs:    []string{"foo"},
+			wantVerbose: false,
+			wantSteps:   0,
+			wantErr:     nil,
+		},
+		"required-global-flag": {
+			args:    []string{"db", "migrate"},
+			env:     map[string]string{},
+			wantErr: act.ErrRequired,
+		},
+		"no-command": {
+			args:    []string{"-dsn", "postgres://"},
+			env:     map[string]string{},
+			wantErr: act.ErrNoCommand,
+		},
+		"unknown-command": {
+			args:    []string{"db", "drop"},
+			env:     map[string]string{},
+			wantErr: act.ErrUnknownCommand,
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			lookupEnvFunc := func(env string) (string, bool) {
+				v, ok := tt.env[env]
+
+				return v, ok
+			}
+
+			var (
+				called string
+				args   []string
+			)
+
+			global := &globalConfig{}   //nolint:exhaustruct
+			migrate := &migrateConfig{} //nolint:exhaustruct
+
+			app := act.New("app", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(&bytes.Buffer{}),
+				act.WithLookupEnvFunc(lookupEnvFunc))
+
+			db := app.Command("db", nil, nil)
+			db.Command("migrate", migrate, func(a []string) error {
+				called, args = "migrate", a
+
+				return nil
+			})
+			app.Command("serve", nil, func(a []string) error {
+				called, args = "serve", a
+
+				return nil
+			})
+
+			err := app.Run(global, tt.args)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("want error %v got %v", tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if called != tt.wantCalled {
+				t.Errorf("want called %q got %q", tt.wantCalled, called)
+			}
+
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("want args %v got %v", tt.wantArgs, args)
+			}
+
+			if global.Verbose != tt.wantVerbose {
+				t.Errorf("want verbose %t got %t", tt.wantVerbose, global.Verbose)
+			}
+
+			if migrate.Steps != tt.wantSteps {
+				t.Errorf("want steps %d got %d", tt.wantSteps, migrate.Steps)
+			}
+		})
+	}
+}
+
+func TestRun_handlerError(t *testing.T) {
+	t.Parallel()
+
+	errHandler := errors.New("handler")
+
+	app := act.New("app", act.WithErrorHandling(flag.ContinueOnError))
+	app.Command("serve", nil, func([]string) error { return errHandler })
+
+	if err := app.Run(nil, []string{"serve"}); !errors.Is(err, errHandler) {
+		t.Errorf("want error %v got %v", errHandler, err)
+	}
+}
+
+func TestRun_usage(t *testing.T) {
+	t.Parallel()
+
+	ws := regexp.MustCompile(`\s+`)
+
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"root": {
+			args: []string{"-h"},
+			want: `Usage of app: -dsn string dsn (env APP_DSN) (required) -verbose verbose (env APP_VERBOSE)
+Commands: db database management serve`,
+		},
+		"subcommand": {
+			args: []string{"db", "migrate", "-h"},
+			want: `Usage of app db migrate: -dsn string dsn (env APP_DSN) (required)
+-steps int steps (env APP_DB_MIGRATE_STEPS) (default 1) -verbose verbose (env APP_VERBOSE)`,
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			b := &bytes.Buffer{}
+
+			app := act.New("app", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b))
+			db := app.Command("db", nil, nil, act.WithDescription("database management"))
+			db.Command("migrate", &migrateConfig{}, nil) //nolint:exhaustruct
+			app.Command("serve", nil, nil)
+
+			if err := app.Run(&globalConfig{}, tt.args); err != nil { //nolint:exhaustruct
+				t.Fatal(err)
+			}
+
+			got := strings.TrimSpace(ws.ReplaceAllString(b.String(), " "))
+			if want := ws.ReplaceAllString(tt.want, " "); got != want {
+				t.Errorf("\ngot %q\nwant %q\n", got, want)
+			}
+		})
+	}
+}
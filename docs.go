@@ -0,0 +1,321 @@
+package act
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// GenerateMarkdown writes reference documentation of the command and its subcommands in Markdown format, listing
+// flags, environment variables, types, default values and descriptions of all the fields. Config of the command is
+// passed the same way as to Parse or Run and may be nil if the command has no config of its own, while subcommands
+// document the configs stored by Command. Only default values of fresh copies of the configs are used, so the output
+// doesn't depend on the environment and it may be run by go generate without parsing the command line first.
+func (a *Act) GenerateMarkdown(w io.Writer, config interface{}) error {
+	helps, err := a.docs(config, nil)
+	if err != nil {
+		return err
+	}
+
+	b := &bytes.Buffer{}
+
+	for i, h := range helps {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		writeMarkdown(b, h)
+	}
+
+	if _, err := b.WriteTo(w); err != nil {
+		return fmt.Errorf("writing markdown: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateManPage writes reference documentation of the command and its subcommands as a man page in troff format,
+// see GenerateMarkdown.
+func (a *Act) GenerateManPage(w io.Writer, config interface{}) error {
+	helps, err := a.docs(config, nil)
+	if err != nil {
+		return err
+	}
+
+	b := &bytes.Buffer{}
+
+	fmt.Fprintf(b, ".TH %s 1\n", troffEscape(strings.ToUpper(helps[0].Name)))
+
+	for i, h := range helps {
+		writeManPage(b, h, i == 0)
+	}
+
+	if _, err := b.WriteTo(w); err != nil {
+		return fmt.Errorf("writing man page: %w", err)
+	}
+
+	return nil
+}
+
+// docs parses fresh copies of the configs of the command tree in help mode, so only default values are used, and
+// returns help models of all the commands, parents first.
+func (a *Act) docs(config interface{}, parent *Act) ([]*Help, error) {
+	if config == nil {
+		config = &struct{}{}
+	}
+
+	t := reflect.TypeOf(config)
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil, ErrInvalidConfigType
+	}
+
+	d := New(a.name, a.opts...)
+	d.parent = parent
+	d.help = true
+
+	// The config flag has no default value taken from the environment.
+	d.lookupEnvFunc = func(string) (string, bool) { return "", false }
+
+	if parent != nil {
+		d.flagSet.Init(fmt.Sprintf("%s %s", parent.flagSet.Name(), a.name), flag.ContinueOnError)
+	}
+
+	if err := d.initSources(nil); err != nil {
+		return nil, err
+	}
+
+	if err := d.parse(reflect.New(t.Elem()).Interface(), nil, ""); err != nil {
+		return nil, err
+	}
+
+	if len(d.errs) > 0 {
+		return nil, d.errs
+	}
+
+	// Flags of the parent commands are documented only once by the parents.
+	d.commands = a.commands
+
+	helps := []*Help{d.helpModel()}
+
+	for _, c := range a.commands {
+		h, err := c.docs(c.config, d)
+		if err != nil {
+			return nil, err
+		}
+
+		helps = append(helps, h...)
+	}
+
+	return helps, nil
+}
+
+// usage formats the usage line, i.e. "app [flags] SRC [DST]".
+func (h *Help) usage() string {
+	s := h.Name
+	if len(h.Groups) > 0 {
+		s += " [flags]"
+	}
+
+	if len(h.Commands) > 0 {
+		s += " <command>"
+	}
+
+	for _, arg := range h.Arguments {
+		name := arg.Name
+		if arg.Rest {
+			name += "..."
+		}
+
+		if !arg.Required {
+			name = fmt.Sprintf("[%s]", name)
+		}
+
+		s += " " + name
+	}
+
+	return s
+}
+
+// dash returns the prefix of the flag names.
+func (h *Help) dash() string {
+	if h.POSIX {
+		return "--"
+	}
+
+	return "-"
+}
+
+func writeMarkdown(b *bytes.Buffer, h *Help) {
+	fmt.Fprintf(b, "# %s\n\n", h.Name)
+
+	if h.Description != "" {
+		fmt.Fprintf(b, "%s\n\n", h.Description)
+	}
+
+	fmt.Fprintf(b, "```\n%s\n```\n", h.usage())
+
+	for _, g := range h.Groups {
+		name := g.Name
+		if name == "" {
+			name = "Flags"
+		}
+
+		fmt.Fprintf(b, "\n## %s\n\n| Flag | Environment variable | Type | Default | Description |\n", name)
+		b.WriteString("| --- | --- | --- | --- | --- |\n")
+
+		for _, f := range g.Fields {
+			flags := fmt.Sprintf("`%s%s`", h.dash(), f.Flag)
+			if f.Short != "" {
+				flags = fmt.Sprintf("`-%s`, %s", f.Short, flags)
+			}
+
+			fmt.Fprintf(b, "| %s | %s | %s | %s | %s |\n", flags, markdownCode(f.Env), markdownCode(f.Type),
+				markdownCode(f.Default), markdownEscape(fieldHelp(f)))
+		}
+	}
+
+	if len(h.Arguments) > 0 {
+		b.WriteString("\n## Arguments\n\n| Argument | Description |\n| --- | --- |\n")
+
+		for _, arg := range h.Arguments {
+			fmt.Fprintf(b, "| `%s` | %s |\n", arg.Name, markdownEscape(arg.Help))
+		}
+	}
+
+	if len(h.Commands) > 0 {
+		b.WriteString("\n## Commands\n\n")
+
+		for _, c := range h.Commands {
+			name := fmt.Sprintf("%s %s", h.Name, c.Name)
+			fmt.Fprintf(b, "- [%s](#%s)", name, strings.ToLower(strings.ReplaceAll(name, " ", "-")))
+
+			if c.Description != "" {
+				fmt.Fprintf(b, " - %s", c.Description)
+			}
+
+			b.WriteString("\n")
+		}
+	}
+
+	if len(h.Examples) > 0 {
+		fmt.Fprintf(b, "\n## Examples\n\n```\n%s\n```\n", strings.Join(h.Examples, "\n"))
+	}
+}
+
+func writeManPage(b *bytes.Buffer, h *Help, root bool) {
+	if root {
+		fmt.Fprintf(b, ".SH NAME\n%s", troffEscape(h.Name))
+
+		if h.Description != "" {
+			fmt.Fprintf(b, " \\- %s", troffEscape(h.Description))
+		}
+
+		fmt.Fprintf(b, "\n.SH SYNOPSIS\n%s\n", troffEscape(h.usage()))
+	} else {
+		fmt.Fprintf(b, ".SH %s\n", troffEscape(strings.ToUpper(h.Name)))
+
+		if h.Description != "" {
+			fmt.Fprintf(b, "%s\n.PP\n", troffEscape(h.Description))
+		}
+
+		fmt.Fprintf(b, "%s\n", troffEscape(h.usage()))
+	}
+
+	if len(h.Groups) > 0 && root {
+		b.WriteString(".SH OPTIONS\n")
+	}
+
+	for _, g := range h.Groups {
+		if g.Name != "" || !root {
+			name := g.Name
+			if name == "" {
+				name = "Options"
+			}
+
+			fmt.Fprintf(b, ".SS %s\n", troffEscape(name))
+		}
+
+		for _, f := range g.Fields {
+			b.WriteString(".TP\n")
+
+			if f.Short != "" {
+				fmt.Fprintf(b, "\\fB\\-%s\\fR, ", troffEscape(f.Short))
+			}
+
+			fmt.Fprintf(b, "\\fB%s\\fR \\fI%s\\fR\n%s\n", troffEscape(h.dash()+f.Flag), troffEscape(f.Type),
+				troffEscape(fieldHelp(f)))
+
+			if f.Env != "" {
+				fmt.Fprintf(b, ".br\nEnvironment variable: %s\n", troffEscape(f.Env))
+			}
+
+			if f.Default != "" {
+				fmt.Fprintf(b, ".br\nDefault: %s\n", troffEscape(f.Default))
+			}
+		}
+	}
+
+	if len(h.Arguments) > 0 {
+		b.WriteString(".SS Arguments\n")
+
+		for _, arg := range h.Arguments {
+			fmt.Fprintf(b, ".TP\n\\fI%s\\fR\n%s\n", troffEscape(arg.Name), troffEscape(arg.Help))
+		}
+	}
+
+	if len(h.Commands) > 0 {
+		b.WriteString(".SS Commands\n")
+
+		for _, c := range h.Commands {
+			fmt.Fprintf(b, ".TP\n\\fB%s\\fR\n%s\n", troffEscape(c.Name), troffEscape(c.Description))
+		}
+	}
+
+	if len(h.Examples) > 0 {
+		b.WriteString(".SS Examples\n.nf\n")
+
+		for _, e := range h.Examples {
+			fmt.Fprintf(b, "%s\n", troffEscape(e))
+		}
+
+		b.WriteString(".fi\n")
+	}
+}
+
+// fieldHelp returns the description of the field with the required mark.
+func fieldHelp(f HelpField) string {
+	if f.Required {
+		return f.Help + " (required)"
+	}
+
+	return f.Help
+}
+
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("`%s`", strings.ReplaceAll(s, "|", "\\|"))
+}
+
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
+
+// troffEscape escapes backslashes and dashes and prevents lines from being interpreted as requests.
+func troffEscape(s string) string {
+	s = strings.NewReplacer("\\", "\\e", "-", "\\-").Replace(s)
+
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if strings.HasPrefix(l, ".") || strings.HasPrefix(l, "'") {
+			lines[i] = "\\&" + l
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
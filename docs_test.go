@@ -0,0 +1,124 @@
+package act_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+
+	"go.ectobit.com/act"
+)
+
+type docsConfig struct {
+	Port  int `def:"8080" req:"true"`
+	Mongo struct {
+		URL      string `def:"mongodb://localhost"`
+		Password string `def:"pass"`
+	}
+}
+
+func newDocsApp() *act.Act {
+	lookupEnvFunc := func(string) (string, bool) {
+		return "from-env", true
+	}
+
+	app := act.New("app", act.WithErrorHandling(flag.ContinueOnError), act.WithLookupEnvFunc(lookupEnvFunc),
+		act.WithDescription("cool service"), act.WithConfigFlag())
+
+	migrate := &struct {
+		Steps  int    `short:"s" def:"1" help:"number of steps | all"`
+		Target string `arg:"0"`
+	}{}
+
+	app.Command("migrate", migrate, func(args []string) error { return nil },
+		act.WithDescription("run migrations"), act.WithExamples("app migrate -steps 2 latest"))
+
+	return app
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	if err := newDocsApp().GenerateMarkdown(b, &docsConfig{}); err != nil { //nolint:exhaustruct
+		t.Fatal(err)
+	}
+
+	want := "# app\n\ncool service\n\n```\napp [flags] <command>\n```\n" + `
+## Flags
+
+| Flag | Environment variable | Type | Default | Description |
+| --- | --- | --- | --- | --- |
+| ` + "`-port` | `APP_PORT` | `int` | `8080`" + ` | port (required) |
+| ` + "`-config` |  | `string`" + ` |  | config file (env APP_CONFIG) |
+
+## Mongo
+
+| Flag | Environment variable | Type | Default | Description |
+| --- | --- | --- | --- | --- |
+| ` + "`-mongo-url` | `APP_MONGO_URL` | `string` | `mongodb://localhost`" + ` | mongo url |
+| ` + "`-mongo-password` | `APP_MONGO_PASSWORD` | `string` | `******`" + ` | mongo password |
+
+## Commands
+
+- [app migrate](#app-migrate) - run migrations
+
+# app migrate
+
+run migrations
+
+` + "```\napp migrate [flags] TARGET\n```" + `
+
+## Flags
+
+| Flag | Environment variable | Type | Default | Description |
+| --- | --- | --- | --- | --- |
+| ` + "`-s`, `-steps` | `APP_MIGRATE_STEPS` | `int` | `1`" + ` | number of steps \| all |
+
+## Arguments
+
+| Argument | Description |
+| --- | --- |
+| ` + "`TARGET`" + ` | target |
+
+## Examples
+
+` + "```\napp migrate -steps 2 latest\n```\n"
+
+	if b.String() != want {
+		t.Errorf("want\n%s\ngot\n%s", want, b.String())
+	}
+}
+
+func TestGenerateManPage(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	if err := newDocsApp().GenerateManPage(b, &docsConfig{}); err != nil { //nolint:exhaustruct
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		".TH APP 1\n.SH NAME\napp \\- cool service\n.SH SYNOPSIS\napp [flags] <command>\n.SH OPTIONS\n",
+		".TP\n\\fB\\-port\\fR \\fIint\\fR\nport (required)\n.br\nEnvironment variable: APP_PORT\n.br\nDefault: 8080\n",
+		".SS Mongo\n",
+		".SH APP MIGRATE\nrun migrations\n.PP\napp migrate [flags] TARGET\n.SS Options\n",
+		".TP\n\\fB\\-s\\fR, \\fB\\-steps\\fR \\fIint\\fR\nnumber of steps | all\n",
+		".SS Examples\n.nf\napp migrate \\-steps 2 latest\n.fi\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("want %q in\n%s", want, b.String())
+		}
+	}
+}
+
+func TestGenerateMarkdown_invalidConfig(t *testing.T) {
+	t.Parallel()
+
+	if err := newDocsApp().GenerateMarkdown(&bytes.Buffer{}, docsConfig{}); !errors.Is(err, act.ErrInvalidConfigType) { //nolint:exhaustruct,lll
+		t.Errorf("want error %v got %v", act.ErrInvalidConfigType, err)
+	}
+}
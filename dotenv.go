@@ -0,0 +1,379 @@
+package act
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DotEnvSource creates source reading environment variables using supplied function, i.e. os.LookupEnv, and
+// falling back to the variables defined in dotenv files. Files are listed in the order of precedence and those
+// which don't exist are skipped. Map fields collect families of variables the same way as in EnvSource.
+func DotEnvSource(lookupEnvFunc func(string) (string, bool), paths ...string) Source {
+	return &dotEnvSource{lookupEnvFunc: lookupEnvFunc, environFunc: os.Environ, paths: paths, values: nil,
+		owned: nil}
+}
+
+type dotEnvSource struct {
+	lookupEnvFunc func(string) (string, bool)
+	environFunc   func() []string
+	paths         []string
+	values        map[string]*dotEnvValue
+	owned         []Field
+}
+
+type dotEnvValue struct {
+	value string
+	path  string
+	line  int
+}
+
+func (*dotEnvSource) Name() string {
+	return "env"
+}
+
+func (s *dotEnvSource) Lookup(field Field) (string, bool, error) {
+	v, ok := s.lookup(field.Env)
+	if ok {
+		return v, ok, nil
+	}
+
+	environ := s.environFunc()
+	for k := range s.values {
+		environ = append(environ, k)
+	}
+
+	v, ok = lookupEnvFamily(field, s.lookup, environ, s.owned)
+
+	return v, ok, nil
+}
+
+func (s *dotEnvSource) own(fields []Field) {
+	s.owned = fields
+}
+
+func (s *dotEnvSource) checkKeys(fields []Field) []*FieldError {
+	environ := s.environFunc()
+	for k := range s.values {
+		environ = append(environ, k)
+	}
+
+	names := unknownElements(environ, s.lookup, fields, s.owned)
+	errs := make([]*FieldError, 0, len(names))
+
+	for _, name := range names {
+		errs = append(errs, unknownEnvError(name, s.location(Field{Path: "", Flag: "", Env: name, Tag: "", Type: nil})))
+	}
+
+	return errs
+}
+
+func (s *dotEnvSource) load() error {
+	s.values = map[string]*dotEnvValue{}
+
+	for _, path := range s.paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return fmt.Errorf("reading dotenv file: %w", err)
+		}
+
+		p := &dotEnvParser{data: string(data), pos: 0, line: 1, lookup: s.lookup}
+
+		if err := p.parse(func(key, value string, line int) {
+			if _, ok := s.values[key]; !ok {
+				s.values[key] = &dotEnvValue{value: value, path: path, line: line}
+			}
+		}); err != nil {
+			return fmt.Errorf("parsing dotenv file %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *dotEnvSource) location(field Field) string {
+	if _, ok := s.lookupEnvFunc(field.Env); ok {
+		return ""
+	}
+
+	if fv, ok := s.values[field.Env]; ok {
+		return fmt.Sprintf("%s:%d", fv.path, fv.line)
+	}
+
+	return ""
+}
+
+func (s *dotEnvSource) files() []string {
+	return s.paths
+}
+
+// lookup returns the value of environment variable, giving the precedence to the process environment.
+func (s *dotEnvSource) lookup(key string) (string, bool) {
+	if v, ok := s.lookupEnvFunc(key); ok {
+		return v, true
+	}
+
+	if fv, ok := s.values[key]; ok {
+		return fv.value, true
+	}
+
+	return "", false
+}
+
+// dotEnvParser parses dotenv syntax: KEY=value lines, optionally prefixed by export, with single quoted literal
+// values, double quoted values with escapes, unquoted values, comments and ${VAR} or $VAR expansion.
+type dotEnvParser struct {
+	data   string
+	pos    int
+	line   int
+	lookup func(string) (string, bool)
+}
+
+// parse calls fn for each variable in order of appearance.
+func (p *dotEnvParser) parse(fn func(key, value string, line int)) error {
+	for {
+		p.skip(" \t\r\n")
+
+		switch {
+		case p.eof():
+			return nil
+		case p.peek() == '#':
+			p.skipLine()
+
+			continue
+		}
+
+		line := p.line
+
+		key, err := p.key()
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		value, err := p.value()
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		fn(key, value, line)
+	}
+}
+
+func (p *dotEnvParser) key() (string, error) {
+	end := strings.IndexAny(p.data[p.pos:], "=\n")
+	if end == -1 || p.data[p.pos+end] != '=' {
+		return "", fmt.Errorf("%w: expected =", ErrDotEnvSyntax)
+	}
+
+	key := strings.TrimSpace(p.data[p.pos : p.pos+end])
+	if strings.HasPrefix(key, "export ") || strings.HasPrefix(key, "export\t") {
+		key = strings.TrimSpace(key[len("export"):])
+	}
+
+	if !isEnvName(key) {
+		return "", fmt.Errorf("%w: invalid name %q", ErrDotEnvSyntax, key)
+	}
+
+	p.pos += end + 1
+
+	return key, nil
+}
+
+func (p *dotEnvParser) value() (string, error) {
+	p.skip(" \t")
+
+	if p.eof() {
+		return "", nil
+	}
+
+	var (
+		value string
+		err   error
+	)
+
+	switch p.peek() {
+	case '\'':
+		value, err = p.singleQuoted()
+	case '"':
+		value, err = p.doubleQuoted()
+	default:
+		return p.unquoted()
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	p.skip(" \t\r")
+
+	if !p.eof() && p.peek() != '\n' && p.peek() != '#' {
+		return "", fmt.Errorf("%w: unexpected characters after quoted value", ErrDotEnvSyntax)
+	}
+
+	p.skipLine()
+
+	return value, nil
+}
+
+func (p *dotEnvParser) singleQuoted() (string, error) {
+	end := strings.IndexByte(p.data[p.pos+1:], '\'')
+	if end == -1 {
+		return "", fmt.Errorf("%w: unterminated single quote", ErrDotEnvSyntax)
+	}
+
+	value := p.data[p.pos+1 : p.pos+1+end]
+	p.line += strings.Count(value, "\n")
+	p.pos += end + 2 //nolint:gomnd
+
+	return value, nil
+}
+
+func (p *dotEnvParser) doubleQuoted() (string, error) { //nolint:cyclop
+	var b strings.Builder
+
+	p.pos++
+
+	for !p.eof() {
+		c := p.next()
+
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if p.eof() {
+				continue
+			}
+
+			switch e := p.next(); e {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\', '$':
+				b.WriteByte(e)
+			default:
+				b.WriteByte(c)
+				b.WriteByte(e)
+			}
+		case '$':
+			if err := p.expand(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", fmt.Errorf("%w: unterminated double quote", ErrDotEnvSyntax)
+}
+
+func (p *dotEnvParser) unquoted() (string, error) {
+	var b strings.Builder
+
+	for !p.eof() && p.peek() != '\n' {
+		c := p.next()
+
+		switch {
+		case c == '#' && (b.Len() == 0 || strings.ContainsRune(" \t", rune(p.data[p.pos-2]))):
+			p.skipLine()
+		case c == '$':
+			if err := p.expand(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return strings.TrimSpace(b.String()), nil
+}
+
+// expand writes the value of the variable referenced after the already consumed $ character.
+func (p *dotEnvParser) expand(b *strings.Builder) error {
+	var name string
+
+	if !p.eof() && p.peek() == '{' {
+		end := strings.IndexAny(p.data[p.pos:], "}\n")
+		if end == -1 || p.data[p.pos+end] != '}' {
+			return fmt.Errorf("%w: unterminated variable reference", ErrDotEnvSyntax)
+		}
+
+		name = p.data[p.pos+1 : p.pos+end]
+		p.pos += end + 1
+	} else {
+		end := p.pos
+		for end < len(p.data) && isEnvNameByte(p.data[end], end > p.pos) {
+			end++
+		}
+
+		name = p.data[p.pos:end]
+		p.pos = end
+	}
+
+	if name == "" {
+		b.WriteByte('$')
+
+		return nil
+	}
+
+	v, _ := p.lookup(name)
+	b.WriteString(v)
+
+	return nil
+}
+
+func (p *dotEnvParser) eof() bool {
+	return p.pos >= len(p.data)
+}
+
+func (p *dotEnvParser) peek() byte {
+	return p.data[p.pos]
+}
+
+func (p *dotEnvParser) next() byte {
+	c := p.data[p.pos]
+	p.pos++
+
+	if c == '\n' {
+		p.line++
+	}
+
+	return c
+}
+
+func (p *dotEnvParser) skip(chars string) {
+	for !p.eof() && strings.IndexByte(chars, p.peek()) != -1 {
+		p.next()
+	}
+}
+
+func (p *dotEnvParser) skipLine() {
+	for !p.eof() && p.peek() != '\n' {
+		p.next()
+	}
+}
+
+func isEnvName(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if !isEnvNameByte(s[i], i > 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isEnvNameByte(c byte, digit bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (digit && c >= '0' && c <= '9')
+}
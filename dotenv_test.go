@@ -0,0 +1,147 @@
+package act_test
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"go.ectobit.com/act"
+)
+
+func TestWithDotEnv(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	type config struct {
+		Host     string
+		Port     uint
+		Name     string
+		Greeting string
+		Path     string
+		Multi    string
+		Extra    string
+	}
+
+	path := writeFile(t, ".env", `# local settings
+export TEST_HOST=example.com
+TEST_PORT = 4000 # inline comment
+TEST_NAME='literal $TEST_HOST\n'
+TEST_GREETING="hello\t${TEST_USER}\n\"quoted\" \$TEST_HOST"
+TEST_PATH=$TEST_HOST/path#fragment
+TEST_MULTI="line1
+line2" # comment
+`)
+	local := writeFile(t, ".env.local", `TEST_HOST=other.com
+TEST_EXTRA=extra
+`)
+
+	lookupEnvFunc := func(env string) (string, bool) {
+		switch env {
+		case "TEST_PORT":
+			return "5000", true
+		case "TEST_USER":
+			return "joe", true
+		}
+
+		return "", false
+	}
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithLookupEnvFunc(lookupEnvFunc),
+		act.WithDotEnv(path, filepath.Join(t.TempDir(), "missing.env"), local))
+
+	cfg := &config{} //nolint:exhaustruct
+
+	if err := a.Parse(cfg, []string{}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := &config{
+		Host:     "example.com",
+		Port:     5000,
+		Name:     `literal $TEST_HOST\n`,
+		Greeting: "hello\tjoe\n\"quoted\" $TEST_HOST",
+		Path:     "example.com/path#fragment",
+		Multi:    "line1\nline2",
+		Extra:    "extra",
+	}
+
+	if *cfg != *want {
+		t.Errorf("\ngot  %#v\nwant %#v", cfg, want)
+	}
+
+	for _, o := range a.Origins() {
+		if o.Path == "Multi" && (o.Source != "env" || o.Location != path+":7") {
+			t.Errorf("want location %s:7 got %+v", path, o)
+		}
+	}
+}
+
+func TestWithDotEnv_errors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		content   string
+		wantErr   string
+		wantErrIs error
+	}{
+		"unterminated-quote": {
+			content:   "TEST_HOST=localhost\nTEST_NAME=\"foo\n\n",
+			wantErr:   "parsing dotenv file %s: line 2: invalid dotenv syntax: unterminated double quote",
+			wantErrIs: act.ErrDotEnvSyntax,
+		},
+		"missing-equals": {
+			content:   "# comment\nTEST_HOST\n",
+			wantErr:   "parsing dotenv file %s: line 2: invalid dotenv syntax: expected =",
+			wantErrIs: act.ErrDotEnvSyntax,
+		},
+		"invalid-name": {
+			content:   "TEST-HOST=localhost\n",
+			wantErr:   `parsing dotenv file %s: line 1: invalid dotenv syntax: invalid name "TEST-HOST"`,
+			wantErrIs: act.ErrDotEnvSyntax,
+		},
+		"characters-after-quote": {
+			content:   "TEST_HOST='local'host\n",
+			wantErr:   "parsing dotenv file %s: line 1: invalid dotenv syntax: unexpected characters after quoted value",
+			wantErrIs: act.ErrDotEnvSyntax,
+		},
+		"invalid-value": {
+			content:   "TEST_HOST=localhost\n\nTEST_PORT=a\n",
+			wantErr:   `Port env %s:3: parsing uint "a": strconv.ParseUint: parsing "a": invalid syntax`,
+			wantErrIs: nil,
+		},
+		"element-gap": {
+			content:   "TEST_BACKENDS_0_HOST=a\nTEST_BACKENDS_2_HOST=c\n",
+			wantErr:   "TEST_BACKENDS_2_HOST env %s:2: unknown config key",
+			wantErrIs: act.ErrUnknownKey,
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			path := writeFile(t, ".env", tt.content)
+
+			a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithDotEnv(path),
+				act.WithLookupEnvFunc(func(string) (string, bool) { return "", false }))
+
+			err := a.Parse(&struct { //nolint:exhaustruct
+				Host     string
+				Port     uint
+				Backends []struct{ Host string }
+			}{}, []string{})
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("want error %v got %v", tt.wantErrIs, err)
+			}
+
+			if want := fmt.Sprintf(tt.wantErr, path); err == nil || err.Error() != want {
+				t.Errorf("want error %q got %v", want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package act
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldError is an error of a single config field. Err may be checked against ErrRequired, ErrUnknownKey and
+// ErrValidation using errors.Is.
+type FieldError struct {
+	// Path is dot separated path of the struct field, i.e. "DB.Port". In case of unknown key, it is the key itself.
+	Path string
+	// Flag is command line flag name, i.e. "db-port".
+	Flag string
+	// Env is environment variable name, i.e. "MYCMD_DB_PORT".
+	Env string
+	// Source is the name of the source of the value, i.e. "flag", "env" or "def". It is empty if value is missing.
+	Source string
+	// Location is the exact location of the value if known by the source, i.e. file name and line.
+	Location string
+	// Value is the raw value which failed to parse.
+	Value string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error formats the error. Path is empty in case of error of the root config struct.
+func (e *FieldError) Error() string {
+	s := e.Path
+
+	for _, p := range []string{e.Source, e.Location} {
+		if p != "" {
+			s = strings.TrimSpace(fmt.Sprintf("%s %s", s, p))
+		}
+	}
+
+	if s == "" {
+		return e.Err.Error()
+	}
+
+	return fmt.Sprintf("%s: %v", s, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON formats the error as JSON object, i.e. for structured logs.
+func (e *FieldError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct { //nolint:wrapcheck
+		Path     string `json:"path"`
+		Flag     string `json:"flag,omitempty"`
+		Env      string `json:"env,omitempty"`
+		Source   string `json:"source,omitempty"`
+		Location string `json:"location,omitempty"`
+		Value    string `json:"value,omitempty"`
+		Err      string `json:"error"`
+	}{
+		Path:     e.Path,
+		Flag:     e.Flag,
+		Env:      e.Env,
+		Source:   e.Source,
+		Location: e.Location,
+		Value:    e.Value,
+		Err:      e.Err.Error(),
+	})
+}
+
+// ParseErrors holds errors of all fields which failed to parse.
+type ParseErrors []*FieldError
+
+// Error formats all the errors.
+func (e ParseErrors) Error() string {
+	s := make([]string, 0, len(e))
+	for _, fe := range e {
+		s = append(s, fe.Error())
+	}
+
+	return strings.Join(s, "; ")
+}
+
+// Is reports whether any of the errors matches the target.
+func (e ParseErrors) Is(target error) bool {
+	for _, fe := range e {
+		if errors.Is(fe, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first error matching the target.
+func (e ParseErrors) As(target interface{}) bool {
+	for _, fe := range e {
+		if errors.As(fe, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mask replaces values of the secret fields in help, errors and origins.
+const mask = "******"
+
+// secretError hides the secret value in the message of the underlying error. Items are elements of slices and keys
+// and values of maps, which are hidden only if quoted, so the rest of the message is kept intact.
+type secretError struct {
+	err   error
+	value string
+	items []string
+}
+
+// Error formats the error replacing the secret value and its items by the mask.
+func (e *secretError) Error() string {
+	s := strings.ReplaceAll(e.err.Error(), e.value, mask)
+
+	for _, item := range e.items {
+		s = strings.ReplaceAll(s, strconv.Quote(item), strconv.Quote(mask))
+	}
+
+	return s
+}
+
+// Unwrap returns the underlying error.
+func (e *secretError) Unwrap() error {
+	return e.err
+}
+
+// errorValue records errors of the flag values, so the parsing of flags continues with the next flag.
+type errorValue struct {
+	flag.Value
+	field *fieldMeta
+	dash  string
+	errs  *ParseErrors
+}
+
+// Set sets flag's value recording the error if any.
+func (f *errorValue) Set(s string) error {
+	if err := f.Value.Set(s); err != nil {
+		*f.errs = append(*f.errs, f.field.error("flag", "", s, f.flagError(s, err)))
+	}
+
+	return nil
+}
+
+// flagError adds the value and the flag name to the error of the flag package, i.e. `invalid value "x" for flag
+// -port: parse error`. Overflow error of the numeric flag, which lacks the allowed range, is replaced by the error of
+// setValue.
+func (f *errorValue) flagError(s string, err error) error {
+	invalid := fmt.Errorf("invalid value %q for flag %s%s: %w", s, f.dash, f.field.Flag, err)
+
+	g, ok := f.Value.(flag.Getter)
+	if !ok {
+		return invalid
+	}
+
+	t := reflect.TypeOf(g.Get())
+	if t == nil || t == durationType {
+		return invalid
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if perr := setValue(reflect.New(t).Elem(), s); errors.Is(perr, strconv.ErrRange) {
+			return perr
+		}
+	}
+
+	return invalid
+}
+
+// IsBoolFlag allows boolean flags to be set without a value.
+func (f *errorValue) IsBoolFlag() bool {
+	bf, ok := f.Value.(interface{ IsBoolFlag() bool })
+
+	return ok && bf.IsBoolFlag()
+}
+
+// wrapFlags wraps values of the flags belonging to the fields to record their errors.
+func (a *Act) wrapFlags() {
+	dash := "-"
+	if a.posix {
+		dash = "--"
+	}
+
+	a.flagSet.VisitAll(func(f *flag.Flag) {
+		for c := a; c != nil; c = c.parent {
+			for _, field := range c.fields {
+				if field.Flag == f.Name {
+					f.Value = &errorValue{Value: unwrapValue(f.Value), field: field, dash: dash, errs: &a.errs}
+
+					return
+				}
+			}
+		}
+	})
+}
+
+// printDefaults prints flags defaults with original values, so the flag package recognizes their types. In POSIX
+// mode flag names are prefixed by double dash, i.e. "--port".
+func (a *Act) printDefaults() {
+	wrapped := map[*flag.Flag]flag.Value{}
+
+	a.flagSet.VisitAll(func(f *flag.Flag) {
+		if v, ok := f.Value.(*errorValue); ok {
+			wrapped[f] = v
+			f.Value = v.Value
+		}
+	})
+
+	if a.posix {
+		b := &strings.Builder{}
+
+		a.flagSet.SetOutput(b)
+		a.flagSet.PrintDefaults()
+		a.flagSet.SetOutput(a.output)
+
+		lines := strings.SplitAfter(b.String(), "\n")
+		for i, line := range lines {
+			if strings.HasPrefix(line, "  -") {
+				lines[i] = "  --" + line[3:]
+			}
+		}
+
+		fmt.Fprint(a.output, strings.Join(lines, ""))
+	} else {
+		a.flagSet.PrintDefaults()
+	}
+
+	for f, v := range wrapped {
+		f.Value = v
+	}
+}
+
+func unwrapValue(v flag.Value) flag.Value {
+	if ev, ok := v.(*errorValue); ok {
+		return ev.Value
+	}
+
+	return v
+}
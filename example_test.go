@@ -37,8 +37,8 @@ func Example_basic() {
 
 func Example_advanced() {
 	type config struct {
-		Env   string `help:"environment [development|production]" def:"development"`
-		Port  uint   `def:"3000"`
+		Env   string `help:"environment [development|production]" def:"development" oneof:"development,production"`
+		Port  uint   `def:"3000" min:"1" max:"65535"`
 		Mongo struct {
 			Hosts             act.StringSlice `def:"mongo"`
 			ConnectionTimeout time.Duration   `def:"10s"`
@@ -0,0 +1,364 @@
+package act
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// configFile holds flattened values of a JSON, YAML or TOML config file keyed by normalized field path.
+type configFile struct {
+	name   string
+	values map[string]*fileValue
+}
+
+// listSep separates items of arrays of scalars, which are joined by the separator of the field on lookup.
+const listSep = "\x1f"
+
+// fileValue is a single raw value of a config file together with its position.
+type fileValue struct {
+	key   string
+	value string
+	line  int
+}
+
+func loadConfigFile(path string) (*configFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	cf := &configFile{name: path, values: map[string]*fileValue{}}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = cf.parseJSON(data)
+	case ".yaml", ".yml":
+		err = cf.parseYAML(data)
+	case ".toml":
+		err = cf.parseTOML(data)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrConfigFormat, path)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+
+	return cf, nil
+}
+
+// lookup returns raw value for the supplied field, i.e. "DB.Postgres.Host". Items of arrays are joined by the
+// separator of the field.
+func (cf *configFile) lookup(field Field) (string, bool) {
+	if cf == nil {
+		return "", false
+	}
+
+	fv, ok := cf.values[normalizeKey(field.Path)]
+	if ok {
+		return strings.ReplaceAll(fv.value, listSep, separator(field.Tag)), true
+	}
+
+	if field.Type == nil || field.Type.Kind() != reflect.Map {
+		return "", false
+	}
+
+	// Objects are joined into key=value items of the map field.
+	n := strings.Count(field.Path, ".") + 1
+
+	var items []string
+
+	for _, fv := range cf.family(field.Path) {
+		items = append(items, fmt.Sprintf("%s=%s", strings.SplitN(fv.key, ".", n+1)[n], fv.value))
+	}
+
+	sort.Strings(items)
+
+	return strings.Join(items, separator(field.Tag)), items != nil
+}
+
+// family returns values of the object keyed by the supplied path.
+func (cf *configFile) family(path string) []*fileValue {
+	prefix := normalizeKey(path) + "."
+
+	var values []*fileValue
+
+	for k, fv := range cf.values {
+		if strings.HasPrefix(k, prefix) {
+			values = append(values, fv)
+		}
+	}
+
+	return values
+}
+
+// location returns file name and line of the value for the supplied field path.
+func (cf *configFile) location(path string) string {
+	fv, ok := cf.values[normalizeKey(path)]
+	if !ok || fv.line == 0 {
+		return cf.name
+	}
+
+	return fmt.Sprintf("%s:%d", cf.name, fv.line)
+}
+
+// unknown returns errors of all keys which are not mapped to any of the supplied fields. Values are omitted, since
+// a misspelled key of a secret can't be recognized as secret.
+func (cf *configFile) unknown(fields []Field) []*FieldError {
+	if cf == nil {
+		return nil
+	}
+
+	known := make(map[string]struct{}, len(fields))
+
+	for _, f := range fields {
+		known[normalizeKey(f.Path)] = struct{}{}
+
+		if f.Type != nil && f.Type.Kind() == reflect.Map {
+			for _, fv := range cf.family(f.Path) {
+				known[normalizeKey(fv.key)] = struct{}{}
+			}
+		}
+	}
+
+	var unused []*fileValue
+
+	for k, fv := range cf.values {
+		if _, ok := known[k]; !ok {
+			unused = append(unused, fv)
+		}
+	}
+
+	sort.Slice(unused, func(i, j int) bool {
+		if unused[i].line != unused[j].line {
+			return unused[i].line < unused[j].line
+		}
+
+		return unused[i].key < unused[j].key
+	})
+
+	errs := make([]*FieldError, 0, len(unused))
+
+	for _, fv := range unused {
+		errs = append(errs, &FieldError{
+			Path:     fv.key,
+			Flag:     "",
+			Env:      "",
+			Source:   "file",
+			Location: cf.location(fv.key),
+			Value:    "",
+			Err:      ErrUnknownKey,
+		})
+	}
+
+	return errs
+}
+
+func (cf *configFile) add(key, value string, line int) {
+	cf.values[normalizeKey(key)] = &fileValue{key: key, value: value, line: line}
+}
+
+func (cf *configFile) parseJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	line := func() int {
+		return bytes.Count(data[:dec.InputOffset()], []byte("\n")) + 1
+	}
+
+	if _, _, err := cf.parseJSONValue(dec, "", line); err != nil {
+		var se *json.SyntaxError
+		if errors.As(err, &se) {
+			return fmt.Errorf("line %d: %w", bytes.Count(data[:se.Offset], []byte("\n"))+1, err)
+		}
+
+		return err
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("line %d: unexpected data after top-level value", line())
+	}
+
+	return nil
+}
+
+// parseJSONValue reads the next value from the decoder. Objects and arrays of objects are flattened into the
+// config file values, while scalars and arrays of scalars are returned to the caller.
+func (cf *configFile) parseJSONValue(dec *json.Decoder, key string, line func() int) (string, bool, error) { //nolint:cyclop
+	tok, err := dec.Token()
+	if err != nil {
+		return "", false, fmt.Errorf("reading token: %w", err)
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		if tok == nil {
+			return "", false, nil
+		}
+
+		return fmt.Sprint(tok), true, nil
+	}
+
+	var items []string
+
+	for i := 0; dec.More(); i++ {
+		k := joinKey(key, strconv.Itoa(i))
+
+		if delim == '{' {
+			tok, err := dec.Token()
+			if err != nil {
+				return "", false, fmt.Errorf("reading token: %w", err)
+			}
+
+			k = joinKey(key, tok.(string)) //nolint:forcetypeassert
+		}
+
+		l := line()
+
+		value, ok, err := cf.parseJSONValue(dec, k, line)
+		if err != nil {
+			return "", false, err
+		}
+
+		switch {
+		case !ok:
+		case delim == '{':
+			cf.add(k, value, l)
+		default:
+			items = append(items, value)
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return "", false, fmt.Errorf("reading token: %w", err)
+	}
+
+	return strings.Join(items, listSep), items != nil, nil
+}
+
+func (cf *configFile) parseYAML(data []byte) error {
+	var doc yaml.Node
+
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("unmarshaling yaml: %w", err)
+	}
+
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	cf.parseYAMLNode(doc.Content[0], "", doc.Content[0].Line)
+
+	return nil
+}
+
+func (cf *configFile) parseYAMLNode(node *yaml.Node, key string, line int) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	switch node.Kind { //nolint:exhaustive
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			cf.parseYAMLNode(node.Content[i+1], joinKey(key, node.Content[i].Value), node.Content[i].Line)
+		}
+	case yaml.SequenceNode:
+		var items []string
+
+		for i, item := range node.Content {
+			if item.Kind == yaml.MappingNode || item.Kind == yaml.SequenceNode {
+				cf.parseYAMLNode(item, joinKey(key, strconv.Itoa(i)), item.Line)
+
+				continue
+			}
+
+			items = append(items, item.Value)
+		}
+
+		if items != nil {
+			cf.add(key, strings.Join(items, listSep), line)
+		}
+	case yaml.ScalarNode:
+		if node.ShortTag() != "!!null" {
+			cf.add(key, node.Value, line)
+		}
+	}
+}
+
+func (cf *configFile) parseTOML(data []byte) error {
+	var m map[string]interface{}
+
+	if _, err := toml.Decode(string(data), &m); err != nil {
+		return fmt.Errorf("decoding toml: %w", err)
+	}
+
+	cf.parseTOMLValue(m, "")
+
+	return nil
+}
+
+func (cf *configFile) parseTOMLValue(value interface{}, key string) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for k, v := range value {
+			cf.parseTOMLValue(v, joinKey(key, k))
+		}
+	case []map[string]interface{}:
+		for i, v := range value {
+			cf.parseTOMLValue(v, joinKey(key, strconv.Itoa(i)))
+		}
+	case []interface{}:
+		items := make([]string, 0, len(value))
+
+		for i, v := range value {
+			if _, ok := v.(map[string]interface{}); ok {
+				cf.parseTOMLValue(v, joinKey(key, strconv.Itoa(i)))
+
+				continue
+			}
+
+			items = append(items, tomlString(v))
+		}
+
+		if len(items) > 0 {
+			cf.add(key, strings.Join(items, listSep), 0)
+		}
+	default:
+		cf.add(key, tomlString(value), 0)
+	}
+}
+
+func tomlString(v interface{}) string {
+	if t, ok := v.(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+
+	return fmt.Sprint(v)
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return fmt.Sprintf("%s.%s", prefix, key)
+}
+
+// normalizeKey makes config file keys and field paths comparable, so "max_pool_size", "max-pool-size" and
+// "maxPoolSize" all match field MaxPoolSize.
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(key))
+}
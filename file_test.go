@@ -0,0 +1,283 @@
+package act_test
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.ectobit.com/act"
+)
+
+type fileConfig struct {
+	Host string `def:"localhost"`
+	Port uint   `def:"3000"`
+	DB   struct {
+		Hosts       act.StringSlice
+		MaxPoolSize uint64 `def:"100"`
+		Timeout     time.Duration
+	}
+}
+
+func TestWithConfigFile(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	tests := map[string]struct {
+		name    string
+		content string
+	}{
+		"json": {
+			name: "config.json",
+			content: `{
+  "port": 8080,
+  "db": {
+    "hosts": ["mongo1", "mongo2"],
+    "max_pool_size": 5,
+    "timeout": "5s"
+  }
+}`,
+		},
+		"yaml": {
+			name: "config.yaml",
+			content: `port: 8080
+db:
+  hosts:
+    - mongo1
+    - mongo2
+  maxPoolSize: 5
+  timeout: 5s
+`,
+		},
+		"toml": {
+			name: "config.toml",
+			content: `port = 8080
+
+[db]
+hosts = ["mongo1", "mongo2"]
+max-pool-size = 5
+timeout = "5s"
+`,
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			path := writeFile(t, tt.name, tt.content)
+
+			lookupEnvFunc := func(env string) (string, bool) {
+				if env == "TEST_PORT" {
+					return "9090", true
+				}
+
+				return "", false
+			}
+
+			a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithLookupEnvFunc(lookupEnvFunc),
+				act.WithConfigFile(path))
+
+			cfg := &fileConfig{} //nolint:exhaustruct
+
+			if err := a.Parse(cfg, []string{"-db-max-pool-size", "10"}); err != nil {
+				t.Fatal(err)
+			}
+
+			if cfg.Host != "localhost" {
+				t.Errorf("want host localhost got %s", cfg.Host)
+			}
+
+			if cfg.Port != 9090 {
+				t.Errorf("want port 9090 got %d", cfg.Port)
+			}
+
+			if len(cfg.DB.Hosts) != 2 || cfg.DB.Hosts[0] != "mongo1" || cfg.DB.Hosts[1] != "mongo2" {
+				t.Errorf("want hosts [mongo1 mongo2] got %v", cfg.DB.Hosts)
+			}
+
+			if cfg.DB.MaxPoolSize != 10 {
+				t.Errorf("want max pool size 10 got %d", cfg.DB.MaxPoolSize)
+			}
+
+			if cfg.DB.Timeout != 5*time.Second {
+				t.Errorf("want timeout 5s got %v", cfg.DB.Timeout)
+			}
+		})
+	}
+}
+
+func TestWithConfigFile_errors(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	tests := map[string]struct {
+		name       string
+		content    string
+		wantErr    string
+		wantErrIs  error
+		wantNoFile bool
+	}{
+		"unknown-key-json": {
+			name:      "config.json",
+			content:   "{\n  \"port\": 1,\n  \"db\": {\n    \"hots\": \"a\"\n  }\n}",
+			wantErr:   "db.hots file %s:4: unknown config key",
+			wantErrIs: act.ErrUnknownKey,
+		},
+		"unknown-keys-yaml": {
+			name:      "config.yaml",
+			content:   "foo: 1\ndb:\n  bar: 2\n",
+			wantErr:   "foo file %[1]s:1: unknown config key; db.bar file %[1]s:3: unknown config key",
+			wantErrIs: act.ErrUnknownKey,
+		},
+		"unknown-key-toml": {
+			name:      "config.toml",
+			content:   "foo = 1\n",
+			wantErr:   "foo file %s: unknown config key",
+			wantErrIs: act.ErrUnknownKey,
+		},
+		"invalid-value-yaml": {
+			name:      "config.yaml",
+			content:   "port: 1\ndb:\n  timeout: a\n",
+			wantErr:   `DB.Timeout file %s:3: parsing duration "a": time: invalid duration "a"`,
+			wantErrIs: nil,
+		},
+		"syntax-json": {
+			name:      "config.json",
+			content:   "{\n  \"port\": 1\n  \"host\": \"a\"\n}",
+			wantErr:   "parsing config file %s: line 3: reading token: invalid character '\"' after object key:value pair", //nolint:lll
+			wantErrIs: nil,
+		},
+		"unsupported-format": {
+			name:      "config.ini",
+			content:   "port=1",
+			wantErr:   "unsupported config file format: %s",
+			wantErrIs: act.ErrConfigFormat,
+		},
+		"missing-file": {
+			name:       "config.json",
+			wantErrIs:  os.ErrNotExist,
+			wantNoFile: true,
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), tt.name)
+			if !tt.wantNoFile {
+				path = writeFile(t, tt.name, tt.content)
+			}
+
+			a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithConfigFile(path))
+
+			err := a.Parse(&fileConfig{}, []string{}) //nolint:exhaustruct
+			if err == nil {
+				t.Fatal("want error got no error")
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("want error %v got %v", tt.wantErrIs, err)
+			}
+
+			if tt.wantErr == "" {
+				return
+			}
+
+			if want := fmt.Sprintf(tt.wantErr, path); err.Error() != want {
+				t.Errorf("want error %q got %q", want, err.Error())
+			}
+		})
+	}
+}
+
+func TestWithConfigFile_unknownSecret(t *testing.T) {
+	t.Parallel()
+
+	path := writeFile(t, "config.yaml", "db:\n  pasword: hunter2\n")
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithConfigFile(path))
+
+	err := a.Parse(&fileConfig{}, []string{}) //nolint:exhaustruct
+
+	var pe act.ParseErrors
+	if !errors.As(err, &pe) || len(pe) != 1 || !errors.Is(err, act.ErrUnknownKey) {
+		t.Fatalf("want one unknown key error got %v", err)
+	}
+
+	got, err := json.Marshal(pe[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := fmt.Sprintf(`{"path":"db.pasword","source":"file","location":"%s:2","error":"unknown config key"}`, path)
+	if string(got) != want {
+		t.Errorf("want json %s got %s", want, got)
+	}
+}
+
+func TestWithConfigFlag(t *testing.T) {
+	t.Parallel()
+
+	path := writeFile(t, "config.json", `{"host": "example.com"}`)
+
+	tests := map[string]struct {
+		flags []string
+		env   string
+	}{
+		"flag":        {flags: []string{"-config", path}, env: ""},
+		"long-flag":   {flags: []string{"--config=" + path}, env: ""},
+		"environment": {flags: []string{}, env: path},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			lookupEnvFunc := func(env string) (string, bool) {
+				if env == "TEST_CONFIG" && tt.env != "" {
+					return tt.env, true
+				}
+
+				return "", false
+			}
+
+			a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithLookupEnvFunc(lookupEnvFunc),
+				act.WithConfigFlag())
+
+			cfg := &fileConfig{} //nolint:exhaustruct
+
+			if err := a.Parse(cfg, tt.flags); err != nil {
+				t.Fatal(err)
+			}
+
+			if cfg.Host != "example.com" {
+				t.Errorf("want host example.com got %s", cfg.Host)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
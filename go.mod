@@ -2,4 +2,11 @@ module go.ectobit.com/act
 
 go 1.17
 
-require github.com/iancoleman/strcase v0.2.0
+require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/iancoleman/strcase v0.2.0
+	golang.org/x/term v0.0.0-20220722155259-a9ba230a4035
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sys v0.10.0 // indirect
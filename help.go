@@ -0,0 +1,330 @@
+package act
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// defaultHelpWidth is the width of the grouped help output if the output is not a terminal and COLUMNS environment
+// variable is not set.
+const defaultHelpWidth = 80
+
+// Help is the model of the command passed to the help template, see WithHelpTemplate.
+type Help struct {
+	// Name is the command name prefixed by the names of parent commands, i.e. "app db migrate".
+	Name string
+	// Description is set by WithDescription option.
+	Description string
+	// Groups hold the flags grouped by the nested structs.
+	Groups []HelpGroup
+	// Arguments are positional arguments in order of their indexes.
+	Arguments []HelpArgument
+	// Commands are subcommands.
+	Commands []HelpCommand
+	// Examples are set by WithExamples option.
+	Examples []string
+	// POSIX reports whether long flags require double dash, see WithPOSIXFlags.
+	POSIX bool
+}
+
+// HelpGroup holds the flags of a nested struct, i.e. "Mongo". Name of the group of top level fields is empty.
+type HelpGroup struct {
+	Name   string
+	Fields []HelpField
+}
+
+// HelpField describes a single flag.
+type HelpField struct {
+	Flag     string
+	Short    string
+	Env      string
+	Type     string
+	Default  string
+	Help     string
+	Required bool
+}
+
+// HelpArgument describes a positional argument. Rest is set for the field collecting the rest of the arguments.
+type HelpArgument struct {
+	Name     string
+	Help     string
+	Required bool
+	Rest     bool
+}
+
+// HelpCommand describes a subcommand.
+type HelpCommand struct {
+	Name        string
+	Description string
+}
+
+// helpModel creates the model of the command for the help template.
+func (a *Act) helpModel() *Help {
+	h := &Help{
+		Name:        a.flagSet.Name(),
+		Description: a.description,
+		Groups:      a.helpGroups(),
+		Arguments:   make([]HelpArgument, 0, len(a.args)),
+		Commands:    make([]HelpCommand, 0, len(a.commands)),
+		Examples:    a.examples,
+		POSIX:       a.posix,
+	}
+
+	for _, arg := range a.args {
+		h.Arguments = append(h.Arguments, HelpArgument{
+			Name:     arg.field.arg,
+			Help:     arg.field.help,
+			Required: arg.field.required,
+			Rest:     arg.index == -1,
+		})
+	}
+
+	for _, c := range a.commands {
+		h.Commands = append(h.Commands, HelpCommand{Name: c.name, Description: c.description})
+	}
+
+	return h
+}
+
+// helpGroups collects the flags of the command and its parents grouped by the nested structs in order of their
+// definition. Flags not belonging to any field, like config flag, are added to the top level group.
+func (a *Act) helpGroups() []HelpGroup {
+	var groups []HelpGroup
+
+	index := map[string]int{}
+	seen := map[string]bool{}
+
+	add := func(group string, hf HelpField) {
+		seen[hf.Flag] = true
+
+		i, ok := index[group]
+		if !ok {
+			i = len(groups)
+			index[group] = i
+			groups = append(groups, HelpGroup{Name: group, Fields: nil})
+		}
+
+		groups[i].Fields = append(groups[i].Fields, hf)
+	}
+
+	for c := a; c != nil; c = c.parent {
+		for _, f := range c.fields {
+			if f.arg != "" || seen[f.Flag] || a.flagSet.Lookup(f.Flag) == nil {
+				continue
+			}
+
+			add(f.group(), f.helpField())
+		}
+	}
+
+	a.flagSet.VisitAll(func(f *flag.Flag) {
+		if seen[f.Name] {
+			return
+		}
+
+		name, usage := flag.UnquoteUsage(f)
+
+		add("", HelpField{
+			Flag:     f.Name,
+			Short:    "",
+			Env:      "",
+			Type:     name,
+			Default:  f.DefValue,
+			Help:     usage,
+			Required: false,
+		})
+	})
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Name == "" && groups[j].Name != ""
+	})
+
+	return groups
+}
+
+// printHelp prints flags grouped by the nested structs in aligned columns, wrapping descriptions to the width of
+// the terminal.
+func (a *Act) printHelp() {
+	groups := a.helpGroups()
+
+	shorts := false
+
+	for _, g := range groups {
+		for _, f := range g.Fields {
+			shorts = shorts || f.Short != ""
+		}
+	}
+
+	header := []string{"FLAG", "TYPE", "DEFAULT", "ENV"}
+	widths := make([]int, len(header))
+
+	measure := func(cols []string) {
+		for i, col := range cols {
+			if len(col) > widths[i] {
+				widths[i] = len(col)
+			}
+		}
+	}
+
+	measure(header)
+
+	for _, g := range groups {
+		for _, f := range g.Fields {
+			measure(a.helpColumns(f, shorts))
+		}
+	}
+
+	fmt.Fprintln(a.output)
+	a.printHelpRow(header, "DESCRIPTION", widths)
+
+	for _, g := range groups {
+		name := g.Name
+		if name == "" {
+			name = "Flags"
+		}
+
+		fmt.Fprintf(a.output, "\n%s:\n", name)
+
+		for _, f := range g.Fields {
+			usage := f.Help
+			if f.Required {
+				usage += " (required)"
+			}
+
+			a.printHelpRow(a.helpColumns(f, shorts), usage, widths)
+		}
+	}
+}
+
+// helpColumns formats the flag with its short alias, type, default value and environment variable.
+func (a *Act) helpColumns(f HelpField, shorts bool) []string {
+	name := "-" + f.Flag
+	if a.posix {
+		name = "--" + f.Flag
+	}
+
+	switch {
+	case f.Short != "":
+		name = fmt.Sprintf("-%s, %s", f.Short, name)
+	case shorts:
+		name = "    " + name
+	}
+
+	return []string{name, f.Type, f.Default, f.Env}
+}
+
+// printHelpRow prints aligned columns followed by the description wrapped to the width of the terminal.
+func (a *Act) printHelpRow(cols []string, usage string, widths []int) {
+	var b strings.Builder
+
+	b.WriteString("  ")
+
+	for i, col := range cols {
+		fmt.Fprintf(&b, "%-*s  ", widths[i], col)
+	}
+
+	indent := b.Len()
+
+	for i, line := range wrap(usage, a.helpWidth()-indent) {
+		if i > 0 {
+			b.WriteString("\n" + strings.Repeat(" ", indent))
+		}
+
+		b.WriteString(line)
+	}
+
+	fmt.Fprintln(a.output, strings.TrimRight(b.String(), " "))
+}
+
+// helpWidth returns the width of the terminal set by COLUMNS environment variable, or the width of the output if it
+// is a terminal, otherwise defaultHelpWidth.
+func (a *Act) helpWidth() int {
+	if s, ok := a.lookupEnvFunc("COLUMNS"); ok {
+		if w, err := strconv.Atoi(s); err == nil && w > 0 {
+			return w
+		}
+	}
+
+	if f, ok := a.output.(interface{ Fd() uintptr }); ok && term.IsTerminal(int(f.Fd())) {
+		if w, _, err := term.GetSize(int(f.Fd())); err == nil && w > 0 {
+			return w
+		}
+	}
+
+	return defaultHelpWidth
+}
+
+// wrap splits the text into lines not longer than the width, unless a single word is longer.
+func wrap(s string, width int) []string {
+	const minWidth = 20
+
+	if width < minWidth {
+		width = minWidth
+	}
+
+	var (
+		lines []string
+		line  string
+	)
+
+	for _, word := range strings.Fields(s) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+
+		if line != "" {
+			line += " "
+		}
+
+		line += word
+	}
+
+	return append(lines, line)
+}
+
+// group returns the path of the nested struct the field belongs to, i.e. "DB.Postgres".
+func (f *fieldMeta) group() string {
+	if i := strings.LastIndex(f.Path, "."); i != -1 {
+		return f.Path[:i]
+	}
+
+	return ""
+}
+
+// helpField describes the field for the help output. Default value is shown only if set by def tag.
+func (f *fieldMeta) helpField() HelpField {
+	def := ""
+	if f.source == "def" {
+		def = f.value
+	}
+
+	if f.secret && def != "" {
+		def = mask
+	}
+
+	return HelpField{
+		Flag:     f.Flag,
+		Short:    f.short,
+		Env:      f.Env,
+		Type:     typeName(f.Type),
+		Default:  def,
+		Help:     f.help,
+		Required: f.required,
+	}
+}
+
+// typeName returns short name of the type, i.e. "duration" or "[]string".
+func typeName(t reflect.Type) string {
+	if t == durationType {
+		return "duration"
+	}
+
+	return t.String()
+}
@@ -0,0 +1,178 @@
+package act_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"go.ectobit.com/act"
+)
+
+func TestWithGroupedHelp(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Env   string `short:"e" def:"development" help:"environment, one of development or production, where development enables verbose logging"` //nolint:lll
+		Port  uint   `short:"p" def:"3000" req:"true"`
+		Mongo struct {
+			Hosts    []string `def:"mongo"`
+			Timeout  time.Duration
+			Password string `def:"pass"`
+		}
+		JWT struct {
+			Secret string
+		}
+	}
+
+	lookupEnvFunc := func(env string) (string, bool) {
+		if env == "COLUMNS" {
+			return "100", true
+		}
+
+		return "", false
+	}
+
+	b := &bytes.Buffer{}
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b),
+		act.WithLookupEnvFunc(lookupEnvFunc), act.WithConfigFlag(), act.WithGroupedHelp())
+
+	if err := a.Parse(&config{}, []string{"-h"}); err != nil { //nolint:exhaustruct
+		t.Fatal(err)
+	}
+
+	want := `Usage of test:
+
+  FLAG                 TYPE      DEFAULT      ENV                  DESCRIPTION
+
+Flags:
+  -e, -env             string    development  TEST_ENV             environment, one of development
+                                                                   or production, where development
+                                                                   enables verbose logging
+  -p, -port            uint      3000         TEST_PORT            port (required)
+      -config          string                                      config file (env TEST_CONFIG)
+
+Mongo:
+      -mongo-hosts     []string  mongo        TEST_MONGO_HOSTS     mongo hosts
+      -mongo-timeout   duration               TEST_MONGO_TIMEOUT   mongo timeout
+      -mongo-password  string    ******       TEST_MONGO_PASSWORD  mongo password
+
+JWT:
+      -jwt-secret      string                 TEST_JWT_SECRET      jwt secret
+`
+
+	if b.String() != want {
+		t.Errorf("want\n%s\ngot\n%s", want, b.String())
+	}
+}
+
+func TestWithHelpTemplate(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Verbose bool   `short:"v"`
+		Steps   int    `def:"1" help:"number of steps"`
+		Target  string `arg:"0"`
+		DB      struct {
+			URL string `req:"true"`
+		}
+	}
+
+	tmpl := template.Must(template.New("help").Parse(`{{.Name}} - {{.Description}}
+{{range .Groups}}[{{if .Name}}{{.Name}}{{else}}global{{end}}]
+{{range .Fields}}--{{.Flag}}{{if .Short}} -{{.Short}}{{end}} ${{.Env}} {{.Help}}{{if .Default}} ({{.Default}}){{end}}` +
+		`{{if .Required}} !{{end}}
+{{end}}{{end}}{{range .Arguments}}<{{.Name}}> {{.Help}}
+{{end}}{{range .Examples}}$ {{.}}
+{{end}}See https://example.com/docs
+`))
+
+	b := &bytes.Buffer{}
+
+	a := act.New("app", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b), act.WithHelpTemplate(tmpl))
+
+	a.Command("migrate", &config{}, func(args []string) error { return nil }, //nolint:exhaustruct
+		act.WithDescription("run migrations"), act.WithExamples("app migrate -steps 2 latest"))
+
+	if err := a.Run(nil, []string{"migrate", "-h"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `app migrate - run migrations
+[global]
+--verbose -v $APP_MIGRATE_VERBOSE verbose
+--steps $APP_MIGRATE_STEPS number of steps (1)
+[DB]
+--db-url $APP_MIGRATE_DB_URL db url !
+<TARGET> target
+$ app migrate -steps 2 latest
+See https://example.com/docs
+`
+
+	if b.String() != want {
+		t.Errorf("want\n%s\ngot\n%s", want, b.String())
+	}
+}
+
+func TestWithExamples(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b),
+		act.WithExamples("test -port 80", "TEST_PORT=80 test"))
+
+	if err := a.Parse(&struct{ Port int }{}, []string{"-h"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `Usage of test:
+  -port int
+    	port (env TEST_PORT)
+
+Examples:
+  test -port 80
+  TEST_PORT=80 test
+`
+
+	if b.String() != want {
+		t.Errorf("want\n%s\ngot\n%s", want, b.String())
+	}
+}
+
+func TestWithGroupedHelp_width(t *testing.T) {
+	t.Parallel()
+
+	// File is not a terminal, so the default width is used.
+	f, err := os.Create(filepath.Join(t.TempDir(), "help.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(f),
+		act.WithLookupEnvFunc(func(string) (string, bool) { return "", false }), act.WithGroupedHelp())
+
+	if err := a.Parse(&struct {
+		Name string `help:"name of the service registered in the service discovery and shown in the logs and metrics"`
+	}{}, []string{"-h"}); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, l := range strings.Split(string(b), "\n") {
+		if len(l) > 80 {
+			t.Errorf("want lines not longer than 80 got %q", l)
+		}
+	}
+}
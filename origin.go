@@ -0,0 +1,78 @@
+package act
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Origin describes where the value of a single config field came from.
+type Origin struct {
+	// Path is dot separated path of the struct field, i.e. "DB.Port".
+	Path string `json:"path"`
+	// Source is the name of the source which has set the value, i.e. "flag", "env", "file" or "def".
+	// It is empty if the value was not set by any source.
+	Source string `json:"source,omitempty"`
+	// Location is the exact location of the value if known by the source, i.e. file name and line.
+	Location string `json:"location,omitempty"`
+	// Value is the raw value as set by the source. Values of the secret fields are masked.
+	Value string `json:"value"`
+	// Flag is command line flag name, i.e. "db-port".
+	Flag string `json:"flag"`
+	// Env is environment variable name, i.e. "MYCMD_DB_PORT".
+	Env string `json:"env"`
+}
+
+// Origins describes origins of all config fields in order of their definition.
+type Origins []Origin
+
+// Origins returns origins of all config fields after successful parsing.
+func (a *Act) Origins() Origins {
+	o := make(Origins, 0, len(a.fields))
+
+	for _, f := range a.fields {
+		value := f.value
+		if f.secret && value != "" {
+			value = mask
+		}
+
+		o = append(o, Origin{
+			Path:     f.Path,
+			Source:   f.source,
+			Location: f.location,
+			Value:    value,
+			Flag:     f.Flag,
+			Env:      f.Env,
+		})
+	}
+
+	return o
+}
+
+// WriteTable writes origins as a table.
+func (o Origins) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint:gomnd
+
+	fmt.Fprintln(tw, "PATH\tSOURCE\tVALUE\tFLAG\tENV")
+
+	for _, f := range o {
+		source := f.Source
+
+		switch {
+		case source == "":
+			source = "-"
+		case f.Location != "":
+			source = fmt.Sprintf("%s (%s)", source, f.Location)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%q\t-%s\t%s\n", f.Path, source, f.Value, f.Flag, f.Env)
+	}
+
+	return tw.Flush() //nolint:wrapcheck
+}
+
+// WriteJSON writes origins as JSON array.
+func (o Origins) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(o) //nolint:wrapcheck
+}
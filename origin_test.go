@@ -0,0 +1,88 @@
+package act_test
+
+import (
+	"bytes"
+	"flag"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"go.ectobit.com/act"
+)
+
+type originConfig struct {
+	Host    string `def:"localhost"`
+	Port    uint   `def:"3000"`
+	Verbose bool
+	Name    string
+	DB      struct {
+		Timeout time.Duration
+	}
+}
+
+func TestAct_Origins(t *testing.T) {
+	t.Parallel()
+
+	path := writeFile(t, "config.json", `{
+  "db": {
+    "timeout": "5s"
+  }
+}`)
+
+	lookupEnvFunc := func(env string) (string, bool) {
+		if env == "TEST_PORT" {
+			return "4000", true
+		}
+
+		return "", false
+	}
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithLookupEnvFunc(lookupEnvFunc),
+		act.WithConfigFile(path))
+
+	if err := a.Parse(&originConfig{}, []string{"-verbose"}); err != nil { //nolint:exhaustruct
+		t.Fatal(err)
+	}
+
+	want := act.Origins{
+		{Path: "Host", Source: "def", Location: "", Value: "localhost", Flag: "host", Env: "TEST_HOST"},
+		{Path: "Port", Source: "env", Location: "", Value: "4000", Flag: "port", Env: "TEST_PORT"},
+		{Path: "Verbose", Source: "flag", Location: "", Value: "true", Flag: "verbose", Env: "TEST_VERBOSE"},
+		{Path: "Name", Source: "", Location: "", Value: "", Flag: "name", Env: "TEST_NAME"},
+		{Path: "DB.Timeout", Source: "file", Location: path + ":3", Value: "5s", Flag: "db-timeout", Env: "TEST_DB_TIMEOUT"}, //nolint:lll
+	}
+
+	got := a.Origins()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot %v\nwant %v\n", got, want)
+	}
+
+	ws := regexp.MustCompile(`\s+`)
+	b := &bytes.Buffer{}
+
+	if err := got.WriteTable(b); err != nil {
+		t.Fatal(err)
+	}
+
+	wantTable := `PATH SOURCE VALUE FLAG ENV Host def "localhost" -host TEST_HOST Port env "4000" -port TEST_PORT
+Verbose flag "true" -verbose TEST_VERBOSE Name - "" -name TEST_NAME
+DB.Timeout file (` + path + `:3) "5s" -db-timeout TEST_DB_TIMEOUT`
+	if table, want := strings.TrimSpace(ws.ReplaceAllString(b.String(), " ")),
+		ws.ReplaceAllString(wantTable, " "); table != want {
+		t.Errorf("\ngot %q\nwant %q\n", table, want)
+	}
+
+	b.Reset()
+
+	if err := got[:2].WriteJSON(b); err != nil {
+		t.Fatal(err)
+	}
+
+	wantJSON := `[{"path":"Host","source":"def","value":"localhost","flag":"host","env":"TEST_HOST"},` +
+		`{"path":"Port","source":"env","value":"4000","flag":"port","env":"TEST_PORT"}]`
+	if strings.TrimSpace(b.String()) != wantJSON {
+		t.Errorf("\ngot %s\nwant %s\n", b.String(), wantJSON)
+	}
+}
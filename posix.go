@@ -0,0 +1,163 @@
+package act
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// shortName returns the short alias of the flag set by "short" tag, i.e. "p". Tags are ignored within the elements
+// of slices, so names stay unique.
+func (*Act) shortName(sf reflect.StructField, prefix string) string {
+	if indexed(prefix) {
+		return ""
+	}
+
+	return sf.Tag.Get("short")
+}
+
+// addShort registers the short alias of the field's flag.
+func (a *Act) addShort(f *fieldMeta, short string) error {
+	if utf8.RuneCountInString(short) != 1 || short == "-" || short == "=" {
+		return fmt.Errorf("%w: %q", ErrInvalidShort, short)
+	}
+
+	if _, ok := a.shorts[short]; ok {
+		return fmt.Errorf("%w (short -%s)", ErrDuplicateField, short)
+	}
+
+	if a.shorts == nil {
+		a.shorts = map[string]string{}
+	}
+
+	a.shorts[short] = f.Flag
+	f.short = short
+
+	return nil
+}
+
+// inheritShorts adds short aliases of the parent commands unless overridden by the command's own aliases.
+func (a *Act) inheritShorts() {
+	if a.parent == nil {
+		return
+	}
+
+	for s, name := range a.parent.shorts {
+		if _, ok := a.shorts[s]; ok {
+			continue
+		}
+
+		if a.shorts == nil {
+			a.shorts = map[string]string{}
+		}
+
+		a.shorts[s] = name
+	}
+}
+
+// translateFlags rewrites short aliases to the flag names, so they are parsed by the flag package, i.e. "-p 80" to
+// "-port 80". In POSIX mode single dash arguments are bundles of short flags, i.e. "-vp80" is "-verbose -port=80",
+// while long flags require double dash. Arguments after the first non-flag argument or the terminator are kept.
+func (a *Act) translateFlags(args []string) []string {
+	translated := make([]string, 0, len(args))
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" || len(arg) < 2 || arg[0] != '-' { //nolint:gomnd
+			return append(translated, args[i:]...)
+		}
+
+		var (
+			name  string
+			value bool
+		)
+
+		switch {
+		case a.posix && strings.HasPrefix(arg, "--"):
+			name, value = a.translateLong(arg)
+			translated = append(translated, arg)
+		case a.posix:
+			var bundle []string
+
+			bundle, name, value = a.translateBundle(arg[1:])
+			translated = append(translated, bundle...)
+		default:
+			// Short aliases may be prefixed by single or double dash, same as flags, i.e. "--p 80".
+			name, value = a.translateLong(arg)
+			if long, ok := a.shorts[name]; ok && a.flagSet.Lookup(name) == nil {
+				dashes := arg[:len(arg)-len(strings.TrimLeft(arg, "-"))]
+				arg = dashes + long + strings.TrimPrefix(arg[len(dashes):], name)
+				name = long
+			}
+
+			translated = append(translated, arg)
+		}
+
+		// The value in the next argument may start with a dash, so it is kept as is, i.e. "--offset -5".
+		if !value && !a.boolFlag(name) && a.flagSet.Lookup(name) != nil && i+1 < len(args) {
+			i++
+			translated = append(translated, args[i])
+		}
+	}
+
+	return translated
+}
+
+// translateLong returns the name of the flag and whether the value is attached, i.e. "--port=80".
+func (*Act) translateLong(arg string) (string, bool) {
+	name := strings.TrimLeft(arg, "-")
+
+	if i := strings.IndexByte(name, '='); i != -1 {
+		return name[:i], true
+	}
+
+	return name, false
+}
+
+// translateBundle translates bundled short flags, i.e. "vp80", returning translated arguments, the name of the last
+// flag and whether its value is attached. Unknown short flags are left to the flag package to report.
+func (a *Act) translateBundle(bundle string) ([]string, string, bool) {
+	var args []string
+
+	for j, r := range bundle {
+		short := string(r)
+
+		name, ok := a.shorts[short]
+		if !ok {
+			name = short
+		}
+
+		if a.flagSet.Lookup(name) == nil {
+			return append(args, "-"+short), "", true
+		}
+
+		if a.boolFlag(name) {
+			args = append(args, "-"+name)
+
+			continue
+		}
+
+		rest := strings.TrimPrefix(bundle[j+len(short):], "=")
+		if rest == "" {
+			return append(args, "-"+name), name, false
+		}
+
+		return append(args, fmt.Sprintf("-%s=%s", name, rest)), name, true
+	}
+
+	return args, "", true
+}
+
+// boolFlag reports whether the flag may be set without a value.
+func (a *Act) boolFlag(name string) bool {
+	f := a.flagSet.Lookup(name)
+	if f == nil {
+		return false
+	}
+
+	bf, ok := f.Value.(interface{ IsBoolFlag() bool })
+
+	return ok && bf.IsBoolFlag()
+}
@@ -0,0 +1,253 @@
+package act_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.ectobit.com/act"
+)
+
+type posixConfig struct {
+	Verbose bool   `short:"v"`
+	Force   bool   `short:"f"`
+	Port    int    `short:"p" def:"8080"`
+	Host    string `def:"localhost"`
+	Offset  int
+	Files   []string `arg:"rest"`
+}
+
+func TestWithPOSIXFlags(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	tests := map[string]struct {
+		posix bool
+		args  []string
+		want  *posixConfig
+	}{
+		"long": {
+			posix: true,
+			args:  []string{"--port=80", "--host", "example.com", "--verbose"},
+			want:  &posixConfig{Verbose: true, Force: false, Port: 80, Host: "example.com", Offset: 0, Files: nil},
+		},
+		"short": {
+			posix: true,
+			args:  []string{"-p", "80", "-v"},
+			want:  &posixConfig{Verbose: true, Force: false, Port: 80, Host: "localhost", Offset: 0, Files: nil},
+		},
+		"bundled": {
+			posix: true,
+			args:  []string{"-vfp80", "a"},
+			want:  &posixConfig{Verbose: true, Force: true, Port: 80, Host: "localhost", Offset: 0, Files: []string{"a"}},
+		},
+		"bundled-value": {
+			posix: true,
+			args:  []string{"-fp", "80", "--offset", "-5"},
+			want:  &posixConfig{Verbose: false, Force: true, Port: 80, Host: "localhost", Offset: -5, Files: nil},
+		},
+		"terminator": {
+			posix: true,
+			args:  []string{"-v", "--", "-p", "80"},
+			want: &posixConfig{
+				Verbose: true, Force: false, Port: 8080, Host: "localhost", Offset: 0, Files: []string{"-p", "80"},
+			},
+		},
+		"standard-short": {
+			posix: false,
+			args:  []string{"-p=80", "-host", "example.com", "-v", "a"},
+			want: &posixConfig{
+				Verbose: true, Force: false, Port: 80, Host: "example.com", Offset: 0, Files: []string{"a"},
+			},
+		},
+		"standard-double-dash-short": {
+			posix: false,
+			args:  []string{"--p", "80", "--v=true", "a"},
+			want: &posixConfig{
+				Verbose: true, Force: false, Port: 80, Host: "localhost", Offset: 0, Files: []string{"a"},
+			},
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			opts := []act.Option{act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(&bytes.Buffer{})}
+			if tt.posix {
+				opts = append(opts, act.WithPOSIXFlags())
+			}
+
+			cfg := &posixConfig{} //nolint:exhaustruct
+
+			if err := act.New("test", opts...).Parse(cfg, tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(cfg, tt.want) {
+				t.Errorf("\ngot  %+v\nwant %+v", cfg, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithPOSIXFlags_precedence(t *testing.T) {
+	t.Parallel()
+
+	lookupEnvFunc := func(env string) (string, bool) {
+		switch env {
+		case "TEST_PORT":
+			return "9090", true
+		case "TEST_HOST":
+			return "env.com", true
+		}
+
+		return "", false
+	}
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithLookupEnvFunc(lookupEnvFunc),
+		act.WithPOSIXFlags())
+
+	cfg := &posixConfig{} //nolint:exhaustruct
+
+	if err := a.Parse(cfg, []string{"-p", "80"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Port != 80 || cfg.Host != "env.com" {
+		t.Errorf("want port 80 and host env.com got %+v", cfg)
+	}
+
+	for _, o := range a.Origins() {
+		if o.Path == "Port" && (o.Source != "flag" || o.Value != "80") {
+			t.Errorf("want port set by flag got %+v", o)
+		}
+	}
+}
+
+func TestWithPOSIXFlags_errors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		config    interface{}
+		args      []string
+		wantErr   string
+		wantErrIs error
+	}{
+		"unknown-short": {
+			config:    &posixConfig{}, //nolint:exhaustruct
+			args:      []string{"-vx"},
+			wantErr:   "flag provided but not defined: -x",
+			wantErrIs: nil,
+		},
+		"single-dash-long": {
+			config:    &posixConfig{}, //nolint:exhaustruct
+			args:      []string{"-offset", "1"},
+			wantErr:   "flag provided but not defined: -o",
+			wantErrIs: nil,
+		},
+		"invalid-value": {
+			config:    &posixConfig{}, //nolint:exhaustruct
+			args:      []string{"--port", "x"},
+			wantErr:   `Port flag: invalid value "x" for flag --port: parse error`,
+			wantErrIs: nil,
+		},
+		"duplicate-short": {
+			config: &struct {
+				Verbose bool `short:"v"`
+				Version bool `short:"v"`
+			}{},
+			args:      []string{},
+			wantErr:   "Version: duplicate field (short -v)",
+			wantErrIs: act.ErrDuplicateField,
+		},
+		"invalid-short": {
+			config: &struct {
+				Verbose bool `short:"vv"`
+			}{},
+			args:      []string{},
+			wantErr:   `Verbose: invalid short flag: "vv"`,
+			wantErrIs: act.ErrInvalidShort,
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(&bytes.Buffer{}),
+				act.WithPOSIXFlags())
+
+			err := a.Parse(tt.config, tt.args)
+			if err == nil {
+				t.Fatal("want error got no error")
+			}
+
+			if !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("want error %q got %q", tt.wantErr, err.Error())
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("want error %v got %v", tt.wantErrIs, err)
+			}
+		})
+	}
+}
+
+func TestWithPOSIXFlags_command(t *testing.T) {
+	t.Parallel()
+
+	global := &struct {
+		Verbose bool `short:"v"`
+	}{}
+
+	migrate := &struct {
+		Steps int `short:"s" def:"1"`
+	}{}
+
+	a := act.New("app", act.WithErrorHandling(flag.ContinueOnError), act.WithPOSIXFlags())
+	a.Command("migrate", migrate, func(args []string) error { return nil })
+
+	if err := a.Run(global, []string{"-v", "migrate", "-vs3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !global.Verbose || migrate.Steps != 3 {
+		t.Errorf("want verbose and 3 steps got %v and %d", global.Verbose, migrate.Steps)
+	}
+}
+
+func TestWithPOSIXFlags_help(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b), act.WithPOSIXFlags())
+
+	if err := a.Parse(&struct {
+		Port int    `short:"p" def:"8080"`
+		Name string `help:"name of the\n-service"`
+	}{}, []string{"-h"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `Usage of test:
+  --name string
+    	name of the
+    	-service (env TEST_NAME)
+  --port int
+    	port (short -p) (env TEST_PORT) (default 8080)
+`
+
+	if b.String() != want {
+		t.Errorf("want\n%s\ngot\n%s", want, b.String())
+	}
+}
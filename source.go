@@ -0,0 +1,311 @@
+package act
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Source provides raw values of config fields, i.e. environment variables, config file or secrets store.
+type Source interface {
+	// Name identifies the source in error messages, i.e. "env".
+	Name() string
+	// Lookup returns raw value of the field and whether it was found.
+	Lookup(field Field) (string, bool, error)
+}
+
+// Field describes a single config value to be resolved by a Source.
+type Field struct {
+	// Path is dot separated path of the struct field, i.e. "DB.Password".
+	Path string
+	// Flag is command line flag name, i.e. "db-password".
+	Flag string
+	// Env is environment variable name, i.e. "MYCMD_DB_PASSWORD".
+	Env string
+	// Tag is the struct field tag.
+	Tag reflect.StructTag
+	// Type is the type of the struct field.
+	Type reflect.Type
+}
+
+// loader is implemented by sources which have to be loaded before the first lookup.
+type loader interface {
+	load() error
+}
+
+// keyChecker is implemented by sources which may contain keys not mapped to any field.
+type keyChecker interface {
+	checkKeys(fields []Field) []*FieldError
+}
+
+// locator is implemented by sources which can tell the exact location of the value, i.e. file name and line.
+type locator interface {
+	location(field Field) string
+}
+
+// watched is implemented by sources reading files which Watch checks for changes.
+type watched interface {
+	files() []string
+}
+
+// owner is implemented by sources collecting families of environment variables of map fields, which have to skip
+// variables owned by other fields of the config.
+type owner interface {
+	own(fields []Field)
+}
+
+// EnvSource creates source reading environment variables using supplied function, i.e. os.LookupEnv.
+// Map fields not set by their environment variable collect the family of variables prefixed by its name,
+// i.e. APP_HEADERS_<KEY>=value, discovered by scanning os.Environ. Variables of other fields are not collected.
+func EnvSource(lookupEnvFunc func(string) (string, bool)) Source {
+	return &envSource{lookupEnvFunc: lookupEnvFunc, environFunc: os.Environ, owned: nil}
+}
+
+type envSource struct {
+	lookupEnvFunc func(string) (string, bool)
+	environFunc   func() []string
+	owned         []Field
+}
+
+func (*envSource) Name() string {
+	return "env"
+}
+
+func (s *envSource) Lookup(field Field) (string, bool, error) {
+	v, ok := s.lookupEnvFunc(field.Env)
+	if ok {
+		return v, ok, nil
+	}
+
+	v, ok = lookupEnvFamily(field, s.lookupEnvFunc, s.environFunc(), s.owned)
+
+	return v, ok, nil
+}
+
+func (s *envSource) own(fields []Field) {
+	s.owned = fields
+}
+
+func (s *envSource) checkKeys(fields []Field) []*FieldError {
+	names := unknownElements(s.environFunc(), s.lookupEnvFunc, fields, s.owned)
+	errs := make([]*FieldError, 0, len(names))
+
+	for _, name := range names {
+		errs = append(errs, unknownEnvError(name, ""))
+	}
+
+	return errs
+}
+
+// lookupEnvFamily joins the variables prefixed by the environment variable name of the map field into the list
+// of key=value items. Names are taken from the environ entries, while values are read by the lookup function.
+// Variables owned by other fields are skipped, i.e. APP_HEADERS_TIMEOUT of HeadersTimeout field.
+func lookupEnvFamily(field Field, lookup func(string) (string, bool), environ []string, owned []Field) (string, bool) {
+	if field.Type == nil || field.Type.Kind() != reflect.Map {
+		return "", false
+	}
+
+	prefix := field.Env + "_"
+
+	var items []string
+
+	for _, e := range environ {
+		name := e
+		if i := strings.IndexByte(e, '='); i != -1 {
+			name = e[:i]
+		}
+
+		if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) || ownedBy(name, field, owned) {
+			continue
+		}
+
+		if v, ok := lookup(name); ok {
+			items = append(items, name[len(prefix):]+"="+v)
+		}
+	}
+
+	sort.Strings(items)
+
+	return strings.Join(items, separator(field.Tag)), items != nil
+}
+
+// ownedBy reports whether the environment variable belongs to any other field, either by its name or by the family of
+// the more specific map field or slice of structs, i.e. APP_HEADERS_EXTRA_<KEY> of HeadersExtra field.
+func ownedBy(name string, field Field, owned []Field) bool {
+	for _, f := range owned {
+		if f.Env == "" || f.Env == field.Env {
+			continue
+		}
+
+		if name == f.Env {
+			return true
+		}
+
+		family := f.Type != nil && (f.Type.Kind() == reflect.Map || isStructSliceType(f.Type))
+		if family && len(f.Env) > len(field.Env) && strings.HasPrefix(name, f.Env+"_") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DefSource creates source reading default values defined by struct tag "def".
+func DefSource() Source {
+	return defSource{}
+}
+
+type defSource struct{}
+
+func (defSource) Name() string {
+	return "def"
+}
+
+func (defSource) Lookup(field Field) (string, bool, error) {
+	v := field.Tag.Get("def")
+
+	return v, v != "", nil
+}
+
+// MapSource creates source reading values from the map keyed by field path, i.e. "DB.Password".
+func MapSource(values map[string]string) Source {
+	return mapSource(values)
+}
+
+type mapSource map[string]string
+
+func (mapSource) Name() string {
+	return "map"
+}
+
+func (s mapSource) Lookup(field Field) (string, bool, error) {
+	v, ok := s[field.Path]
+
+	return v, ok, nil
+}
+
+// FileSource creates source reading JSON, YAML or TOML config file, recognized by the file extension.
+// Keys are matched against the field path, i.e. {"db": {"max_pool_size": 10}} sets field DB.MaxPoolSize.
+// The file is read during parsing and keys not mapped to any field are reported as errors.
+func FileSource(path string) Source {
+	return &fileSource{path: path, file: nil}
+}
+
+type fileSource struct {
+	file *configFile
+	path string
+}
+
+func (*fileSource) Name() string {
+	return "file"
+}
+
+func (s *fileSource) Lookup(field Field) (string, bool, error) {
+	v, ok := s.file.lookup(field)
+
+	return v, ok, nil
+}
+
+func (s *fileSource) load() error {
+	file, err := loadConfigFile(s.path)
+	if err != nil {
+		return err
+	}
+
+	s.file = file
+
+	return nil
+}
+
+func (s *fileSource) checkKeys(fields []Field) []*FieldError {
+	return s.file.unknown(fields)
+}
+
+func (s *fileSource) location(field Field) string {
+	return s.file.location(field.Path)
+}
+
+func (s *fileSource) files() []string {
+	return []string{s.path}
+}
+
+// unknownElements returns sorted names of the variables of the elements of slices of structs which are not mapped to
+// any of the fields, either because of a gap in the indexes or a misspelled name, i.e. APP_BACKENDS_2_HOST without
+// APP_BACKENDS_1_HOST. Names are taken from the environ entries and kept only if found by the lookup function.
+func unknownElements(environ []string, lookup func(string) (string, bool), fields, owned []Field) []string {
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		known[f.Env] = true
+	}
+
+	var unknown []string
+
+	for _, e := range environ {
+		name := e
+		if i := strings.IndexByte(e, '='); i != -1 {
+			name = e[:i]
+		}
+
+		if known[name] || !elementOf(name, owned) || inFamily(name, fields) {
+			continue
+		}
+
+		if _, ok := lookup(name); ok {
+			known[name] = true
+			unknown = append(unknown, name)
+		}
+	}
+
+	sort.Strings(unknown)
+
+	return unknown
+}
+
+// elementOf reports whether the environment variable belongs to an element of any slice of structs, i.e.
+// APP_BACKENDS_<INDEX>_HOST, and not to any other field.
+func elementOf(name string, owned []Field) bool {
+	for _, f := range owned {
+		if f.Env == "" || !isStructSliceType(f.Type) || !strings.HasPrefix(name, f.Env+"_") {
+			continue
+		}
+
+		rest := name[len(f.Env)+1:]
+
+		i := strings.IndexByte(rest, '_')
+		if i <= 0 || strings.Trim(rest[:i], "0123456789") != "" {
+			continue
+		}
+
+		if !ownedBy(name, f, owned) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inFamily reports whether the environment variable belongs to the family of any of the map fields.
+func inFamily(name string, fields []Field) bool {
+	for _, f := range fields {
+		if f.Type != nil && f.Type.Kind() == reflect.Map && strings.HasPrefix(name, f.Env+"_") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unknownEnvError creates an error of the environment variable not mapped to any field. The value is omitted, since
+// a misspelled name of a secret can't be recognized as secret.
+func unknownEnvError(name, location string) *FieldError {
+	return &FieldError{
+		Path:     name,
+		Flag:     "",
+		Env:      name,
+		Source:   "env",
+		Location: location,
+		Value:    "",
+		Err:      ErrUnknownKey,
+	}
+}
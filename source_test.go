@@ -0,0 +1,166 @@
+package act_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+
+	"go.ectobit.com/act"
+)
+
+var errVault = errors.New("vault sealed")
+
+type vaultSource struct {
+	secrets map[string]string
+	err     error
+}
+
+func (*vaultSource) Name() string {
+	return "vault"
+}
+
+func (s *vaultSource) Lookup(field act.Field) (string, bool, error) {
+	if s.err != nil {
+		return "", false, s.err
+	}
+
+	v, ok := s.secrets[field.Path]
+
+	return v, ok, nil
+}
+
+func TestWithSources(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	type config struct {
+		Host string `def:"localhost"`
+		Port uint   `def:"3000"`
+		DB   struct {
+			Password string
+		}
+	}
+
+	env := func(env string) (string, bool) {
+		if env == "TEST_PORT" {
+			return "4000", true
+		}
+
+		return "", false
+	}
+
+	tests := map[string]struct {
+		sources      []act.Source
+		flags        []string
+		wantHost     string
+		wantPort     uint
+		wantPassword string
+	}{
+		"map-before-env": {
+			sources: []act.Source{
+				act.MapSource(map[string]string{"Port": "5000", "DB.Password": "secret"}),
+				act.EnvSource(env),
+				act.DefSource(),
+			},
+			flags:        []string{},
+			wantHost:     "localhost",
+			wantPort:     5000,
+			wantPassword: "secret",
+		},
+		"env-before-map": {
+			sources: []act.Source{
+				act.EnvSource(env),
+				act.MapSource(map[string]string{"Port": "5000", "Host": "example.com"}),
+				act.DefSource(),
+			},
+			flags:        []string{},
+			wantHost:     "example.com",
+			wantPort:     4000,
+			wantPassword: "",
+		},
+		"custom-source-and-flag": {
+			sources: []act.Source{
+				&vaultSource{secrets: map[string]string{"DB.Password": "secret", "Port": "1"}, err: nil},
+				act.DefSource(),
+			},
+			flags:        []string{"-port", "2"},
+			wantHost:     "localhost",
+			wantPort:     2,
+			wantPassword: "secret",
+		},
+		"without-def": {
+			sources:      []act.Source{act.EnvSource(env)},
+			flags:        []string{},
+			wantHost:     "",
+			wantPort:     4000,
+			wantPassword: "",
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithSources(tt.sources...))
+
+			cfg := &config{} //nolint:exhaustruct
+
+			if err := a.Parse(cfg, tt.flags); err != nil {
+				t.Fatal(err)
+			}
+
+			if cfg.Host != tt.wantHost {
+				t.Errorf("want host %q got %q", tt.wantHost, cfg.Host)
+			}
+
+			if cfg.Port != tt.wantPort {
+				t.Errorf("want port %d got %d", tt.wantPort, cfg.Port)
+			}
+
+			if cfg.DB.Password != tt.wantPassword {
+				t.Errorf("want password %q got %q", tt.wantPassword, cfg.DB.Password)
+			}
+		})
+	}
+}
+
+func TestWithSources_errors(t *testing.T) {
+	t.Parallel()
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError),
+		act.WithSources(&vaultSource{secrets: nil, err: errVault}, act.DefSource()))
+
+	err := a.Parse(&struct{ Password string }{}, []string{}) //nolint:exhaustruct
+	if !errors.Is(err, errVault) {
+		t.Fatalf("want error %v got %v", errVault, err)
+	}
+
+	if want := "Password vault: vault sealed"; err.Error() != want {
+		t.Errorf("want error %q got %q", want, err.Error())
+	}
+}
+
+func TestWithSources_help(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(b),
+		act.WithSources(&vaultSource{secrets: map[string]string{"Port": "1"}, err: errVault}, act.DefSource()))
+
+	cfg := &struct {
+		Port uint `def:"2"`
+	}{}
+
+	if err := a.Parse(cfg, []string{"-h"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(b.String(), "(default 2)") {
+		t.Errorf("want default value from def tag got %q", b.String())
+	}
+}
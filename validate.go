@@ -0,0 +1,294 @@
+package act
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validator may be implemented by the config struct or by any of its nested structs to validate the values across
+// multiple fields, i.e. to require a field depending on the value of the other one. Validate is called after all
+// the sources are applied and all the fields are valid.
+type Validator interface {
+	Validate() error
+}
+
+// validatorMeta holds the struct implementing Validator and its path.
+type validatorMeta struct {
+	path      string
+	validator Validator
+	absent    bool
+}
+
+// validatorError wraps the error returned by Validate method, so it matches ErrValidation as well.
+type validatorError struct {
+	err error
+}
+
+// Error formats the error.
+func (e *validatorError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrValidation, e.err)
+}
+
+// Is reports whether the target is ErrValidation.
+func (e *validatorError) Is(target error) bool {
+	return target == ErrValidation //nolint:errorlint,goerr113
+}
+
+// Unwrap returns the error returned by Validate method.
+func (e *validatorError) Unwrap() error {
+	return e.err
+}
+
+// callValidators returns errors of all the structs which Validate method failed, except of absent optional structs.
+func (a *Act) callValidators() ParseErrors {
+	var errs ParseErrors
+
+	for _, v := range a.validators {
+		if v.absent {
+			continue
+		}
+
+		if err := v.validator.Validate(); err != nil {
+			errs = append(errs, &FieldError{
+				Path:     v.path,
+				Flag:     "",
+				Env:      "",
+				Source:   "",
+				Location: "",
+				Value:    "",
+				Err:      &validatorError{err: err},
+			})
+		}
+	}
+
+	return errs
+}
+
+// validate returns errors of all fields which values don't satisfy their validation tags, i.e. `oneof`, `min`,
+// `max`, `pattern` and `scheme`. Fields not set by any source are not validated, missing values are left to `req`.
+func (a *Act) validate() ParseErrors {
+	var errs ParseErrors
+
+	for _, f := range a.fields {
+		if f.absent || !f.set {
+			continue
+		}
+
+		if err := validateField(f); err != nil {
+			errs = append(errs, f.error(f.source, f.location, f.value, err))
+		}
+	}
+
+	return errs
+}
+
+// validateField checks the value of the field by its validation tags.
+func validateField(f *fieldMeta) error { //nolint:cyclop
+	v := f.rv
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	if s, ok := f.Tag.Lookup("oneof"); ok {
+		if err := each(v, func(e reflect.Value) error { return validateOneOf(e, s) }); err != nil {
+			return err
+		}
+	}
+
+	if s, ok := f.Tag.Lookup("min"); ok {
+		if err := validateBound(v, s, "at least", func(c int) bool { return c >= 0 }); err != nil {
+			return err
+		}
+	}
+
+	if s, ok := f.Tag.Lookup("max"); ok {
+		if err := validateBound(v, s, "at most", func(c int) bool { return c <= 0 }); err != nil {
+			return err
+		}
+	}
+
+	if s, ok := f.Tag.Lookup("pattern"); ok {
+		if err := each(v, func(e reflect.Value) error { return validatePattern(e, s) }); err != nil {
+			return err
+		}
+	}
+
+	if s, ok := f.Tag.Lookup("scheme"); ok {
+		return validateScheme(v, s)
+	}
+
+	return nil
+}
+
+// each calls fn for each element of the slice or for the value itself if it is not a slice, like net.IP.
+func each(v reflect.Value, fn func(reflect.Value) error) error {
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 {
+		return fn(v)
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if err := fn(v.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateOneOf(v reflect.Value, tag string) error {
+	s := format(v)
+
+	for _, o := range strings.Split(tag, ",") {
+		if s == strings.TrimSpace(o) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: must be one of %s", ErrValidation, strings.Join(strings.Split(tag, ","), ", "))
+}
+
+func validatePattern(v reflect.Value, tag string) error {
+	re, err := regexp.Compile(tag)
+	if err != nil {
+		return fmt.Errorf("pattern tag: %w", err)
+	}
+
+	if !re.MatchString(format(v)) {
+		return fmt.Errorf("%w: must match %s", ErrValidation, tag)
+	}
+
+	return nil
+}
+
+// validateBound checks the number or the length of string, slice or map against the bound, where ok reports whether
+// the result of comparison of the value with the bound is allowed.
+func validateBound(v reflect.Value, tag, desc string, ok func(int) bool) error { //nolint:cyclop
+	var (
+		c   int
+		err error
+	)
+
+	msg := "must be"
+
+	switch k := v.Kind(); {
+	case v.Type() == durationType:
+		var d time.Duration
+
+		if d, err = time.ParseDuration(tag); err == nil {
+			c = compareInt(v.Int(), int64(d))
+		}
+	case k == reflect.String || k == reflect.Slice || k == reflect.Map:
+		var n int
+
+		if n, err = strconv.Atoi(tag); err == nil {
+			c = compareInt(int64(v.Len()), int64(n))
+			msg = "length must be"
+		}
+	case k >= reflect.Int && k <= reflect.Int64:
+		var n int64
+
+		if n, err = strconv.ParseInt(tag, 10, 64); err == nil {
+			c = compareInt(v.Int(), n)
+		}
+	case k >= reflect.Uint && k <= reflect.Uintptr:
+		var n uint64
+
+		if n, err = strconv.ParseUint(tag, 10, 64); err == nil {
+			c = compareUint(v.Uint(), n)
+		}
+	case k == reflect.Float32 || k == reflect.Float64:
+		var n float64
+
+		if n, err = strconv.ParseFloat(tag, 64); err == nil {
+			c = compareFloat(v.Float(), n)
+		}
+	default:
+		return fmt.Errorf("bound tag %q: %w", tag, ErrUnsupportedType)
+	}
+
+	if err != nil {
+		return fmt.Errorf("bound tag %q: %w", tag, err)
+	}
+
+	if !ok(c) {
+		return fmt.Errorf("%w: %s %s %s", ErrValidation, msg, desc, tag)
+	}
+
+	return nil
+}
+
+func validateScheme(v reflect.Value, tag string) error {
+	u, ok := v.Addr().Interface().(*URL)
+	if !ok {
+		return fmt.Errorf("scheme tag: %w", ErrUnsupportedType)
+	}
+
+	if u.URL == nil {
+		return nil
+	}
+
+	for _, s := range strings.Split(tag, ",") {
+		if strings.EqualFold(u.Scheme, strings.TrimSpace(s)) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: scheme must be one of %s", ErrValidation, strings.Join(strings.Split(tag, ","), ", "))
+}
+
+// format returns string representation of the value used by oneof and pattern tags.
+func format(v reflect.Value) string {
+	if v.CanAddr() {
+		if fv, ok := flagValue(v.Addr().Interface()); ok {
+			return fv.String()
+		}
+	}
+
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,264 @@
+package act_test
+
+import (
+	"errors"
+	"flag"
+	"testing"
+	"time"
+
+	"go.ectobit.com/act"
+)
+
+type validateConfig struct {
+	Env     string          `def:"development" oneof:"development,production"`
+	Port    uint            `def:"3000" min:"1" max:"65535"`
+	Name    string          `def:"app" pattern:"^[a-z]+$"`
+	DSN     act.URL         `def:"postgres://localhost" scheme:"https,postgres"`
+	Hosts   act.StringSlice `def:"mongo" oneof:"mongo,mongo2"`
+	Tags    act.StringSlice `min:"1"`
+	Timeout time.Duration   `def:"1s" max:"1m"`
+	Ratio   float64         `max:"1"`
+	Mode    string          `oneof:"a,b"`
+	Workers int             `min:"1"`
+	Token   *string         `min:"8"`
+	TLS     *struct {
+		Cert string `min:"1"`
+	}
+}
+
+func TestParse_validate(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	tests := map[string]struct {
+		args    []string
+		env     map[string]string
+		wantErr string
+	}{
+		"valid": {
+			args:    []string{"-hosts", "mongo,mongo2", "-ratio", "0.5", "-token", "12345678"},
+			env:     map[string]string{"TEST_TAGS": "a"},
+			wantErr: "",
+		},
+		"oneof": {
+			args:    []string{"-env", "staging"},
+			env:     map[string]string{"TEST_TAGS": "a"},
+			wantErr: "Env flag: validation failed: must be one of development, production",
+		},
+		"oneof-slice-element": {
+			args:    []string{"-hosts", "mongo,redis"},
+			env:     map[string]string{"TEST_TAGS": "a"},
+			wantErr: "Hosts flag: validation failed: must be one of mongo, mongo2",
+		},
+		"min": {
+			args:    []string{},
+			env:     map[string]string{"TEST_TAGS": "a", "TEST_PORT": "0"},
+			wantErr: "Port env: validation failed: must be at least 1",
+		},
+		"max": {
+			args: []string{"-port", "70000", "-ratio", "1.5", "-timeout", "2m"},
+			env:  map[string]string{"TEST_TAGS": "a"},
+			wantErr: "Port flag: validation failed: must be at most 65535; " +
+				"Timeout flag: validation failed: must be at most 1m; " +
+				"Ratio flag: validation failed: must be at most 1",
+		},
+		"pattern": {
+			args:    []string{"-name", "App1"},
+			env:     map[string]string{"TEST_TAGS": "a"},
+			wantErr: "Name flag: validation failed: must match ^[a-z]+$",
+		},
+		"scheme": {
+			args:    []string{"-dsn", "http://localhost"},
+			env:     map[string]string{"TEST_TAGS": "a"},
+			wantErr: "DSN flag: validation failed: scheme must be one of https, postgres",
+		},
+		"unset": {
+			args:    []string{},
+			env:     map[string]string{},
+			wantErr: "",
+		},
+		"empty-slice": {
+			args:    []string{"-tags="},
+			env:     map[string]string{},
+			wantErr: "Tags flag: validation failed: length must be at least 1",
+		},
+		"optional-struct": {
+			args:    []string{"-tls-cert="},
+			env:     map[string]string{"TEST_TAGS": "a"},
+			wantErr: "TLS.Cert flag: validation failed: length must be at least 1",
+		},
+		"secret": {
+			args:    []string{"-token", "1234"},
+			env:     map[string]string{"TEST_TAGS": "a"},
+			wantErr: "Token flag: validation failed: length must be at least 8",
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			lookupEnvFunc := func(env string) (string, bool) {
+				v, ok := tt.env[env]
+
+				return v, ok
+			}
+
+			a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithLookupEnvFunc(lookupEnvFunc))
+
+			err := a.Parse(&validateConfig{}, tt.args) //nolint:exhaustruct
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, act.ErrValidation) {
+				t.Fatalf("want error %v got %v", act.ErrValidation, err)
+			}
+
+			if err.Error() != tt.wantErr {
+				t.Errorf("want error %q got %q", tt.wantErr, err.Error())
+			}
+
+			var fe *act.FieldError
+			if errors.As(err, &fe) && fe.Path == "Token" && fe.Value != "******" {
+				t.Errorf("want masked value got %q", fe.Value)
+			}
+		})
+	}
+}
+
+var errPostgresHost = errors.New("postgres host required")
+
+type dbConfig struct {
+	Kind     string `def:"mongo"`
+	Postgres struct {
+		Host string
+	}
+}
+
+func (c *dbConfig) Validate() error {
+	if c.Kind == "postgres" && c.Postgres.Host == "" {
+		return errPostgresHost
+	}
+
+	return nil
+}
+
+type tlsConfig struct {
+	Cert string
+	Key  string
+}
+
+func (c tlsConfig) Validate() error {
+	if (c.Cert == "") != (c.Key == "") {
+		return errors.New("both cert and key required") //nolint:goerr113
+	}
+
+	return nil
+}
+
+type validatorConfig struct {
+	Port uint `def:"3000" max:"65535"`
+	DB   dbConfig
+	TLS  *tlsConfig
+}
+
+func (c *validatorConfig) Validate() error {
+	if c.TLS != nil && c.Port == 80 {
+		return errors.New("tls on port 80") //nolint:goerr113
+	}
+
+	return nil
+}
+
+func TestParse_validator(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args    []string
+		wantErr string
+	}{
+		"valid": {
+			args:    []string{"-db-kind", "postgres", "-db-postgres-host", "localhost"},
+			wantErr: "",
+		},
+		"nested": {
+			args:    []string{"-db-kind", "postgres"},
+			wantErr: "DB: validation failed: postgres host required",
+		},
+		"optional-and-root": {
+			args:    []string{"-tls-cert", "cert.pem", "-port", "80"},
+			wantErr: "TLS: validation failed: both cert and key required; validation failed: tls on port 80",
+		},
+		"invalid-field": {
+			args:    []string{"-db-kind", "postgres", "-port", "70000"},
+			wantErr: "Port flag: validation failed: must be at most 65535",
+		},
+	}
+
+	for n, tt := range tests { //nolint:paralleltest
+		n := n
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			a := act.New("test", act.WithErrorHandling(flag.ContinueOnError))
+
+			err := a.Parse(&validatorConfig{}, tt.args) //nolint:exhaustruct
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, act.ErrValidation) {
+				t.Fatalf("want error %v got %v", act.ErrValidation, err)
+			}
+
+			if err.Error() != tt.wantErr {
+				t.Errorf("want error %q got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+
+	err := act.New("test", act.WithErrorHandling(flag.ContinueOnError)).
+		Parse(&validatorConfig{}, []string{"-db-kind", "postgres"}) //nolint:exhaustruct
+	if !errors.Is(err, errPostgresHost) {
+		t.Errorf("want error %v got %v", errPostgresHost, err)
+	}
+}
+
+type BaseConfig struct {
+	Name string
+}
+
+func (c *BaseConfig) Validate() error {
+	if c.Name == "" {
+		return errors.New("name required") //nolint:goerr113
+	}
+
+	return nil
+}
+
+func TestParse_validatorEmbedded(t *testing.T) {
+	t.Parallel()
+
+	cfg := &struct {
+		BaseConfig
+		Port int
+	}{}
+
+	err := act.New("test", act.WithErrorHandling(flag.ContinueOnError)).Parse(cfg, []string{})
+	if want := "validation failed: name required"; err == nil || err.Error() != want {
+		t.Errorf("want error %q got %v", want, err)
+	}
+}
@@ -1,8 +1,14 @@
 package act
 
 import (
+	"encoding"
+	"errors"
+	"flag"
 	"fmt"
+	"math"
 	"net/url"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -150,3 +156,426 @@ func (f *Time) String() string {
 func (f *Time) Get() interface{} {
 	return *f.Time
 }
+
+// textValue implements flag.Getter interface for any type implementing encoding.TextUnmarshaler.
+type textValue struct {
+	p encoding.TextUnmarshaler
+}
+
+// Set sets flag's value by unmarshaling provided text.
+func (f *textValue) Set(s string) error {
+	return f.p.UnmarshalText([]byte(s)) //nolint:wrapcheck
+}
+
+// String formats flag's value using encoding.TextMarshaler or fmt.Stringer if implemented.
+func (f *textValue) String() string {
+	if f == nil || f.p == nil {
+		return ""
+	}
+
+	switch p := f.p.(type) {
+	case encoding.TextMarshaler:
+		if b, err := p.MarshalText(); err == nil {
+			return string(b)
+		}
+	case fmt.Stringer:
+		return p.String()
+	}
+
+	return ""
+}
+
+// Get returns flag's value.
+func (f *textValue) Get() interface{} {
+	return f.p
+}
+
+// flagValue returns pointer to a field's value as flag.Value if it implements flag.Value or
+// encoding.TextUnmarshaler interface.
+func flagValue(p interface{}) (flag.Value, bool) {
+	switch p := p.(type) {
+	case flag.Value:
+		return p, true
+	case encoding.TextUnmarshaler:
+		return &textValue{p: p}, true
+	}
+
+	return nil, false
+}
+
+// reflectValue implements flag.Getter interface for any supported kind using reflection.
+type reflectValue struct {
+	v reflect.Value
+}
+
+// Set sets flag's value by parsing provided string according to the value's kind.
+func (f *reflectValue) Set(s string) error {
+	return setValue(f.v, s)
+}
+
+// String formats flag's value.
+func (f *reflectValue) String() string {
+	if f == nil || !f.v.IsValid() {
+		return ""
+	}
+
+	v := f.v
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+
+		v = v.Elem()
+	}
+
+	if fv, ok := flagValue(v.Addr().Interface()); ok {
+		return fv.String()
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+// Get returns flag's value.
+func (f *reflectValue) Get() interface{} {
+	return f.v.Interface()
+}
+
+// IsBoolFlag allows boolean flags to be set without a value.
+func (f *reflectValue) IsBoolFlag() bool {
+	if !f.v.IsValid() {
+		return false
+	}
+
+	t := f.v.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Bool && !isValueType(t)
+}
+
+// sliceValue implements flag.Getter interface for native slices of any supported type, i.e. []string or
+// []time.Duration. The first flag replaces the value set by sources, while repeated flags append to it.
+type sliceValue struct {
+	v   reflect.Value
+	sep string
+	set bool
+}
+
+// Set sets flag's value by splitting provided string by the separator.
+func (f *sliceValue) Set(s string) error {
+	items, err := splitValue(f.v.Type(), s, f.sep)
+	if err != nil {
+		return err
+	}
+
+	if f.set {
+		items = reflect.AppendSlice(f.v, items)
+	}
+
+	f.v.Set(items)
+	f.set = true
+
+	return nil
+}
+
+// String formats flag's value.
+func (f *sliceValue) String() string {
+	if f == nil || !f.v.IsValid() {
+		return ""
+	}
+
+	s := make([]string, 0, f.v.Len())
+	for i := 0; i < f.v.Len(); i++ {
+		s = append(s, (&reflectValue{v: f.v.Index(i)}).String())
+	}
+
+	return strings.Join(s, f.sep)
+}
+
+// Get returns flag's value.
+func (f *sliceValue) Get() interface{} {
+	return f.v.Interface()
+}
+
+// mapValue implements flag.Getter interface for native maps of any supported key and value types, i.e.
+// map[string]int. The first flag replaces the value set by sources, while repeated flags add items to it.
+type mapValue struct {
+	v   reflect.Value
+	sep string
+	set bool
+}
+
+// Set sets flag's value by splitting provided string of key=value items by the separator.
+func (f *mapValue) Set(s string) error {
+	items, err := splitMap(f.v.Type(), s, f.sep)
+	if err != nil {
+		return err
+	}
+
+	if !f.set || f.v.IsNil() {
+		f.v.Set(items)
+		f.set = true
+
+		return nil
+	}
+
+	iter := items.MapRange()
+	for iter.Next() {
+		f.v.SetMapIndex(iter.Key(), iter.Value())
+	}
+
+	return nil
+}
+
+// String formats flag's value as key=value items sorted by the key.
+func (f *mapValue) String() string {
+	if f == nil || !f.v.IsValid() {
+		return ""
+	}
+
+	s := make([]string, 0, f.v.Len())
+
+	iter := f.v.MapRange()
+	for iter.Next() {
+		k := &reflectValue{v: reflect.New(iter.Key().Type()).Elem()}
+		k.v.Set(iter.Key())
+
+		v := &reflectValue{v: reflect.New(iter.Value().Type()).Elem()}
+		v.v.Set(iter.Value())
+
+		s = append(s, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	sort.Strings(s)
+
+	return strings.Join(s, f.sep)
+}
+
+// Get returns flag's value.
+func (f *mapValue) Get() interface{} {
+	return f.v.Interface()
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// isSliceType checks if the type is native slice of supported, but not slice, elements.
+func isSliceType(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice || isValueType(t) {
+		return false
+	}
+
+	e := t.Elem()
+
+	return isSupported(e) && (e.Kind() != reflect.Slice || isValueType(e))
+}
+
+// isStructSliceType checks if the type is native slice of structs, which are not values themselves.
+func isStructSliceType(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && !isValueType(t) && t.Elem().Kind() == reflect.Struct && !isValueType(t.Elem())
+}
+
+// isMapType checks if the type is native map of supported, but not slice, keys and values.
+func isMapType(t reflect.Type) bool {
+	if t.Kind() != reflect.Map || isValueType(t) {
+		return false
+	}
+
+	k, e := t.Key(), t.Elem()
+
+	return isSupported(k) && k.Kind() != reflect.Ptr && isSupported(e) && (e.Kind() != reflect.Slice || isValueType(e))
+}
+
+// splitMap parses the string of key=value items separated by the separator into the map of the type.
+func splitMap(t reflect.Type, s, sep string) (reflect.Value, error) {
+	if s == "" {
+		return reflect.Zero(t), nil
+	}
+
+	items := reflect.MakeMap(t)
+
+	for _, item := range strings.Split(s, sep) {
+		i := strings.IndexByte(item, '=')
+		if i == -1 {
+			return reflect.Value{}, fmt.Errorf("%w: %q", ErrInvalidMapItem, item)
+		}
+
+		k, v := reflect.New(t.Key()).Elem(), reflect.New(t.Elem()).Elem()
+
+		if err := setValue(k, item[:i]); err != nil {
+			return reflect.Value{}, err
+		}
+
+		if err := setValue(v, item[i+1:]); err != nil {
+			return reflect.Value{}, err
+		}
+
+		items.SetMapIndex(k, v)
+	}
+
+	return items, nil
+}
+
+// separator returns separator of the slice elements defined by "sep" tag, comma by default.
+func separator(tag reflect.StructTag) string {
+	if s := tag.Get("sep"); s != "" {
+		return s
+	}
+
+	return ","
+}
+
+// splitValue parses the string of elements separated by the separator into the slice of the type.
+func splitValue(t reflect.Type, s, sep string) (reflect.Value, error) {
+	if s == "" {
+		return reflect.Zero(t), nil
+	}
+
+	items := reflect.MakeSlice(t, 0, 0)
+
+	for _, item := range strings.Split(s, sep) {
+		e := reflect.New(t.Elem()).Elem()
+
+		if err := setValue(e, item); err != nil {
+			return reflect.Value{}, err
+		}
+
+		items = reflect.Append(items, e)
+	}
+
+	return items, nil
+}
+
+// isValueType checks if pointer to the type implements flag.Value or encoding.TextUnmarshaler interface.
+func isValueType(t reflect.Type) bool {
+	_, ok := flagValue(reflect.New(t).Interface())
+
+	return ok
+}
+
+// isSupported checks if the type may be parsed by setValue. Pointers to supported types are supported as well.
+func isSupported(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if isValueType(t) {
+		return true
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// setValue parses the string and sets the result to the addressable value according to its type.
+// Pointers are allocated before the value is set.
+func setValue(v reflect.Value, s string) error { //nolint:cyclop
+	if v.Kind() == reflect.Ptr {
+		p := reflect.New(v.Type().Elem())
+
+		if err := setValue(p.Elem(), s); err != nil {
+			return err
+		}
+
+		v.Set(p)
+
+		return nil
+	}
+
+	if fv, ok := flagValue(v.Addr().Interface()); ok {
+		if err := fv.Set(s); err != nil {
+			return fmt.Errorf("parsing %s %q: %w", v.Type(), s, err)
+		}
+
+		return nil
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("parsing duration %q: %w", s, err)
+		}
+
+		v.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("parsing %s %q: %w", v.Type(), s, err)
+		}
+
+		v.SetBool(b)
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits := v.Type().Bits()
+
+		i, err := strconv.ParseInt(s, 10, bits)
+		if err != nil {
+			return rangeError(v.Type(), s, err, intRange(bits))
+		}
+
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		bits := v.Type().Bits()
+
+		u, err := strconv.ParseUint(s, 10, bits)
+		if err != nil {
+			return rangeError(v.Type(), s, err, uintRange(bits))
+		}
+
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return rangeError(v.Type(), s, err, floatRange(v.Type().Bits()))
+		}
+
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("parsing value: %w: %v", ErrUnsupportedType, v.Type())
+	}
+
+	return nil
+}
+
+// rangeError formats parsing error, replacing strconv error message with the allowed range if the value overflows.
+func rangeError(t reflect.Type, s string, err error, allowed string) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return fmt.Errorf("parsing %s %q: %w %s", t, s, strconv.ErrRange, allowed)
+	}
+
+	return fmt.Errorf("parsing %s %q: %w", t, s, err)
+}
+
+// intRange formats the range of signed integers of the bit size, i.e. "[-128, 127]".
+func intRange(bits int) string {
+	return fmt.Sprintf("[%d, %d]", int64(-1)<<(bits-1), int64(1)<<(bits-1)-1)
+}
+
+// uintRange formats the range of unsigned integers of the bit size, i.e. "[0, 255]".
+func uintRange(bits int) string {
+	return fmt.Sprintf("[0, %d]", uint64(math.MaxUint64)>>(64-bits))
+}
+
+// floatRange formats the range of floats of the bit size.
+func floatRange(bits int) string {
+	maxFloat := math.MaxFloat64
+	if bits == 32 { //nolint:gomnd
+		maxFloat = math.MaxFloat32
+	}
+
+	return fmt.Sprintf("[%g, %g]", -maxFloat, maxFloat)
+}
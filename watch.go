@@ -0,0 +1,244 @@
+package act
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Subscriber is notified with the new config and the paths of the fields which values changed, i.e. "DB.Timeout".
+type Subscriber func(config interface{}, changed []string)
+
+// Watcher keeps the config up to date by parsing the fresh config on SIGHUP signal or on change of any config file.
+// It is safe for concurrent use.
+type Watcher struct {
+	config      atomic.Value
+	newConfig   func() interface{}
+	act         *Act
+	output      io.Writer
+	interval    time.Duration
+	flags       []string
+	subscribers []Subscriber
+	notified    *Act // act of the config the subscribers were last notified about
+	notifying   bool
+	mu          sync.Mutex // serializes reloads
+	smu         sync.Mutex // guards subscribers
+	nmu         sync.Mutex // guards notified and notifying
+}
+
+// Watch parses the config created by newConfig, which has to return pointer to new config struct, and keeps parsing
+// fresh configs in the background when SIGHUP signal arrives or when any config file changes, until the context is
+// done. The previous config is kept if reload fails and the error is written to the output. Configs returned by
+// Watcher are replaced, never modified, so they should be treated as read-only.
+func (a *Act) Watch(ctx context.Context, newConfig func() interface{}, flags []string) (*Watcher, error) {
+	config := newConfig()
+
+	if err := a.Parse(config, flags); err != nil {
+		return nil, err
+	}
+
+	if a.help {
+		return nil, flag.ErrHelp
+	}
+
+	w := &Watcher{ //nolint:exhaustruct
+		newConfig: newConfig,
+		act:       a,
+		notified:  a,
+		output:    a.output,
+		interval:  a.watchInterval,
+		flags:     flags,
+	}
+
+	w.config.Store(config)
+
+	go w.watch(ctx, w.stat())
+
+	return w, nil
+}
+
+// Config returns the current config.
+func (w *Watcher) Config() interface{} {
+	return w.config.Load()
+}
+
+// Subscribe adds the function called after each reload which changed any value.
+func (w *Watcher) Subscribe(fn Subscriber) {
+	w.smu.Lock()
+	defer w.smu.Unlock()
+
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Origins returns origins of all fields of the current config.
+func (w *Watcher) Origins() Origins {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.act.Origins()
+}
+
+// Reload parses the fresh config and replaces the current one if parsing succeeds. Subscribers are notified after
+// the config is replaced and no lock is held, so they may call Origins or Reload. If subscribers are already being
+// notified by a concurrent Reload, it notifies them about this config as well.
+func (w *Watcher) Reload() error {
+	if err := w.reload(); err != nil {
+		return err
+	}
+
+	w.notify()
+
+	return nil
+}
+
+// reload parses the fresh config and replaces the current one.
+func (w *Watcher) reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	opts := append(append([]Option{}, w.act.opts...), WithErrorHandling(flag.ContinueOnError))
+	a := New(w.act.name, opts...)
+	config := w.newConfig()
+
+	if err := a.Parse(config, w.flags); err != nil {
+		return fmt.Errorf("reload: %w", err)
+	}
+
+	w.act = a
+	w.config.Store(config)
+
+	return nil
+}
+
+// notify calls the subscribers with the current config until they are notified about the latest one. Reloads done
+// concurrently or by the subscribers themselves are left to the call already notifying, so the subscribers are
+// notified in order and never end up with an outdated config.
+func (w *Watcher) notify() {
+	w.nmu.Lock()
+
+	if w.notifying {
+		w.nmu.Unlock()
+
+		return
+	}
+
+	w.notifying = true
+
+	for {
+		w.mu.Lock()
+		a, config := w.act, w.config.Load()
+		w.mu.Unlock()
+
+		if a == w.notified {
+			w.notifying = false
+			w.nmu.Unlock()
+
+			return
+		}
+
+		changed := diff(w.notified.fields, a.fields)
+		w.notified = a
+
+		if len(changed) == 0 {
+			continue
+		}
+
+		w.smu.Lock()
+		subscribers := append([]Subscriber{}, w.subscribers...)
+		w.smu.Unlock()
+
+		w.nmu.Unlock()
+
+		for _, fn := range subscribers {
+			fn(config, changed)
+		}
+
+		w.nmu.Lock()
+	}
+}
+
+// watch reloads the config on SIGHUP signal or on change of the modification time or size of any config file.
+func (w *Watcher) watch(ctx context.Context, state map[string]string) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+
+	defer signal.Stop(sig)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sig:
+		case <-ticker.C:
+			s := w.stat()
+			if reflect.DeepEqual(s, state) {
+				continue
+			}
+
+			state = s
+		}
+
+		if err := w.Reload(); err != nil {
+			fmt.Fprintf(w.output, "act: %v\n", err)
+		}
+	}
+}
+
+// stat returns modification times and sizes of the watched files.
+func (w *Watcher) stat() map[string]string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	state := map[string]string{}
+
+	for _, s := range w.act.sources {
+		ws, ok := s.(watched)
+		if !ok {
+			continue
+		}
+
+		for _, f := range ws.files() {
+			fi, err := os.Stat(f)
+			if err != nil {
+				state[f] = err.Error()
+
+				continue
+			}
+
+			state[f] = fmt.Sprintf("%d %d", fi.ModTime().UnixNano(), fi.Size())
+		}
+	}
+
+	return state
+}
+
+// diff returns paths of the fields which values differ.
+func diff(old, fields []*fieldMeta) []string {
+	values := make(map[string]reflect.Value, len(old))
+
+	for _, f := range old {
+		values[f.Path] = f.rv
+	}
+
+	var changed []string
+
+	for _, f := range fields {
+		v, ok := values[f.Path]
+		if !ok || !reflect.DeepEqual(v.Interface(), f.rv.Interface()) {
+			changed = append(changed, f.Path)
+		}
+	}
+
+	return changed
+}
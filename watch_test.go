@@ -0,0 +1,226 @@
+package act_test
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.ectobit.com/act"
+)
+
+type watchConfig struct {
+	Host string `def:"localhost"`
+	Port uint   `def:"3000"`
+	DB   struct {
+		Timeout time.Duration `def:"1s"`
+	}
+}
+
+func TestAct_Watch(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	path := writeFile(t, "config.json", `{"port": 4000}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(io.Discard),
+		act.WithConfigFile(path), act.WithWatchInterval(10*time.Millisecond))
+
+	w, err := a.Watch(ctx, func() interface{} { return &watchConfig{} }, []string{"-host", "example.com"}) //nolint:exhaustruct,lll
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg := w.Config().(*watchConfig); cfg.Port != 4000 || cfg.Host != "example.com" { //nolint:forcetypeassert
+		t.Fatalf("want port 4000 and host example.com got %d and %s", cfg.Port, cfg.Host)
+	}
+
+	type notification struct {
+		config  *watchConfig
+		changed []string
+	}
+
+	ch := make(chan notification, 1)
+
+	w.Subscribe(func(config interface{}, changed []string) {
+		ch <- notification{config: config.(*watchConfig), changed: changed} //nolint:forcetypeassert
+	})
+
+	if err := os.WriteFile(path, []byte(`{"port": 5000, "db": {"timeout": "2s"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case n := <-ch:
+		if want := []string{"Port", "DB.Timeout"}; !reflect.DeepEqual(n.changed, want) {
+			t.Errorf("want changed %v got %v", want, n.changed)
+		}
+
+		if n.config.Port != 5000 || n.config.Host != "example.com" || n.config.DB.Timeout != 2*time.Second {
+			t.Errorf("want reloaded config got %+v", n.config)
+		}
+
+		if w.Config() != n.config {
+			t.Error("want current config replaced")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("want notification")
+	}
+
+	for _, o := range w.Origins() {
+		if o.Path == "Port" && (o.Source != "file" || o.Value != "5000") {
+			t.Errorf("want port origin from file got %+v", o)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(`{"port": "a"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Reload(); err == nil {
+		t.Error("want reload error")
+	}
+
+	if cfg := w.Config().(*watchConfig); cfg.Port != 5000 { //nolint:forcetypeassert
+		t.Errorf("want previous config kept got port %d", cfg.Port)
+	}
+}
+
+func TestWatcher_subscriberOrigins(t *testing.T) {
+	t.Parallel()
+
+	port := "4000"
+
+	var mu sync.Mutex
+
+	lookupEnvFunc := func(env string) (string, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if env == "TEST_PORT" {
+			return port, true
+		}
+
+		return "", false
+	}
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(io.Discard),
+		act.WithLookupEnvFunc(lookupEnvFunc))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w, err := a.Watch(ctx, func() interface{} { return &watchConfig{} }, []string{}) //nolint:exhaustruct
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ch := make(chan act.Origins, 1)
+
+	w.Subscribe(func(config interface{}, changed []string) {
+		ch <- w.Origins()
+	})
+
+	mu.Lock()
+	port = "5000"
+	mu.Unlock()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- w.Reload()
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("want reload to return")
+	}
+
+	for _, o := range <-ch {
+		if o.Path == "Port" && (o.Source != "env" || o.Value != "5000") {
+			t.Errorf("want port origin from env got %+v", o)
+		}
+	}
+}
+
+func TestWatcher_concurrentReloads(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	var port uint32
+
+	lookupEnvFunc := func(env string) (string, bool) {
+		if env == "TEST_PORT" {
+			return strconv.FormatUint(uint64(atomic.AddUint32(&port, 1)), 10), true
+		}
+
+		return "", false
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := act.New("test", act.WithErrorHandling(flag.ContinueOnError), act.WithOutput(io.Discard),
+		act.WithLookupEnvFunc(lookupEnvFunc))
+
+	w, err := a.Watch(ctx, func() interface{} { return &watchConfig{} }, []string{}) //nolint:exhaustruct
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu    sync.Mutex
+		ports []uint
+	)
+
+	w.Subscribe(func(config interface{}, changed []string) {
+		mu.Lock()
+		ports = append(ports, config.(*watchConfig).Port) //nolint:forcetypeassert
+		n := len(ports)
+		mu.Unlock()
+
+		// Reload by the subscriber itself is notified after it returns.
+		if n == 1 {
+			if err := w.Reload(); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := w.Reload(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for i := 1; i < len(ports); i++ {
+		if ports[i] <= ports[i-1] {
+			t.Fatalf("want notifications in order got %v", ports)
+		}
+	}
+
+	if last := ports[len(ports)-1]; last != w.Config().(*watchConfig).Port { //nolint:forcetypeassert
+		t.Errorf("want last notification with current config got %v", ports)
+	}
+}